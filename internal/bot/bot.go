@@ -0,0 +1,264 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/thanhpk/randstr"
+	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/discord"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+// Bot is a server-side player that implements conn.Conn. It reacts to the messages that the game sends,
+// and calls the commands that the game added the same way a real client would.
+// Bots only play game.choose, game.vote and game.decide, every other phase times out for them, so they can't play with game.TimersUnlimited.
+type Bot struct {
+	cl       client.Client
+	strategy Strategy
+	state    State
+
+	log  logger.Logger
+	cmd  map[string]conn.MessageStruct
+	recv chan []byte
+	done []chan bool
+
+	closed bool
+	mtx    sync.Mutex
+}
+
+// view is the part of game.get that the bot cares about.
+type view struct {
+	Players map[string]game.PlayerType `json:"players"`
+	Seats   []string                   `json:"seats"`
+	Rounds  [5]game.Round              `json:"rounds"`
+}
+
+// NewBot returns a new bot that plays with strategy.
+func NewBot(strategy Strategy) *Bot {
+	id := "bot-" + randstr.Hex(8)
+
+	b := &Bot{
+		cl: client.Client{
+			User: discord.User{
+				ID:            id,
+				Username:      "Bot",
+				Discriminator: "0000",
+			},
+		},
+		strategy: strategy,
+		state: State{
+			ID: id,
+		},
+		log:  logger.NullLogger(),
+		cmd:  map[string]conn.MessageStruct{},
+		recv: make(chan []byte, 256),
+	}
+
+	go b.run()
+
+	return b
+}
+
+// run handles the messages in the order they were sent, until the bot gets destroyed.
+func (b *Bot) run() {
+	for body := range b.recv {
+		err := b.handle(body)
+		if err != nil {
+			b.log.Debug("b.handle: %v", err)
+		}
+	}
+}
+
+// handle reacts to a single message from the game.
+func (b *Bot) handle(body []byte) error {
+	msg := conn.MessageRecv{}
+
+	err := json.Unmarshal(body, &msg)
+	if err != nil {
+		return fmt.Errorf("json.Unmarshal: %v", err)
+	}
+
+	if msg.Group != "game" {
+		return nil
+	}
+
+	switch msg.Name {
+	case "get":
+		v := view{}
+		err = json.Unmarshal(msg.Body, &v)
+		if err != nil {
+			return fmt.Errorf("json.Unmarshal: %v", err)
+		}
+
+		b.state.Players = v.Players
+		b.state.Seats = v.Seats
+		b.state.Rounds = v.Rounds
+	case "round":
+		r := game.Round{}
+		err = json.Unmarshal(msg.Body, &r)
+		if err != nil {
+			return fmt.Errorf("json.Unmarshal: %v", err)
+		}
+
+		b.state.Results = append(b.state.Results, r)
+		b.state.Proposal = 0
+	case "choose":
+		var captain string
+		err = json.Unmarshal(msg.Body, &captain)
+		if err != nil {
+			return fmt.Errorf("json.Unmarshal: %v", err)
+		}
+
+		if captain == b.state.ID {
+			return b.execute("choose", b.strategy.Choose(b.state))
+		}
+	case "vote":
+		team := []string{}
+		err = json.Unmarshal(msg.Body, &team)
+		if err != nil {
+			return fmt.Errorf("json.Unmarshal: %v", err)
+		}
+
+		accept := b.strategy.Vote(b.state, team)
+		b.state.Proposal++
+
+		return b.execute("vote", accept)
+	case "decide":
+		team := []string{}
+		err = json.Unmarshal(msg.Body, &team)
+		if err != nil {
+			return fmt.Errorf("json.Unmarshal: %v", err)
+		}
+
+		if contains(team, b.state.ID) {
+			return b.execute("decide", b.strategy.Decide(b.state, team))
+		}
+	case "end":
+		// the lobby could start a rematch, which the bot plays from scratch
+		b.state = State{ID: b.state.ID}
+	}
+
+	return nil
+}
+
+// execute calls a game command, just like a real client sending a message.
+func (b *Bot) execute(name string, body interface{}) error {
+	bytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %v", err)
+	}
+
+	return b.ExecuteCommand("game", name, bytes)
+}
+
+func (b *Bot) AddCommand(group string, msgstrct conn.MessageStruct) {
+	defer b.mtx.Unlock()
+	b.mtx.Lock()
+
+	cmd, ok := b.cmd[group]
+	if ok {
+		for k, v := range msgstrct {
+			cmd[k] = v
+		}
+	} else {
+		b.cmd[group] = msgstrct
+	}
+}
+
+func (b *Bot) ExecuteCommand(group, name string, body []byte) error {
+	b.mtx.Lock()
+	cmd, ok := b.cmd[group][name]
+	// unlock before executing, so the command can add or remove commands
+	b.mtx.Unlock()
+
+	if !ok {
+		return fmt.Errorf("b.cmd[%s][%s].(bool) != true", group, name)
+	}
+
+	return cmd(b.log, body)
+}
+
+func (b *Bot) RemoveCommandsByGroup(group string) {
+	defer b.mtx.Unlock()
+	b.mtx.Lock()
+
+	delete(b.cmd, group)
+}
+
+func (b *Bot) RemoveCommandsByNames(group string, names ...string) {
+	defer b.mtx.Unlock()
+	b.mtx.Lock()
+
+	_, ok := b.cmd[group]
+	if ok {
+		for _, v := range names {
+			delete(b.cmd[group], v)
+		}
+	}
+}
+
+func (b *Bot) WriteMessage(ms conn.MessageSend) error {
+	bytes, err := json.Marshal(ms)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	b.WriteBytes(bytes)
+	return nil
+}
+
+func (b *Bot) WriteBytes(body []byte) {
+	defer b.mtx.Unlock()
+	b.mtx.Lock()
+
+	if b.closed {
+		return
+	}
+
+	// never block the game, a bot that can't keep up just misses the message
+	select {
+	case b.recv <- body:
+	default:
+		b.log.Warn("b.recv is full, dropping message")
+	}
+}
+
+func (b *Bot) GetDone() chan bool {
+	defer b.mtx.Unlock()
+	b.mtx.Lock()
+
+	done := make(chan bool)
+	b.done = append(b.done, done)
+
+	return done
+}
+
+func (b *Bot) Destroy() {
+	b.mtx.Lock()
+	if b.closed {
+		b.mtx.Unlock()
+		return
+	}
+
+	b.closed = true
+	close(b.recv)
+
+	done := b.done
+	b.mtx.Unlock()
+
+	for _, v := range done {
+		v <- true
+	}
+}
+
+func (b *Bot) GetClient() client.Client {
+	return b.cl
+}
+
+func (b *Bot) SetLogger(log logger.Logger) {
+	b.log = log
+}
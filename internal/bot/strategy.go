@@ -0,0 +1,301 @@
+package bot
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+// State is what a bot knows about the game, it's built from the messages that the game sent to the bot.
+// So just like a real client, the players are masked.
+type State struct {
+	// the id of the bot
+	ID string
+	// the players that the bot can see, by id
+	Players map[string]game.PlayerType
+	// the order in which the players sit
+	Seats []string
+	// the rounds as they were sent in game.get, it's used for the amount of assignees
+	Rounds [5]game.Round
+	// the rounds that are finished, in order
+	Results []game.Round
+	// the amount of proposals that have been voted on in the current round
+	Proposal int
+}
+
+// Type returns the player type of the bot.
+func (s State) Type() game.PlayerType {
+	return s.Players[s.ID]
+}
+
+// Round returns the index of the current round.
+func (s State) Round() int {
+	return len(s.Results)
+}
+
+// Size returns the amount of assignees in the current round.
+func (s State) Size() int {
+	ri := s.Round()
+	if ri >= len(s.Rounds) {
+		return 0
+	}
+
+	return int(s.Rounds[ri].Assignees)
+}
+
+// MinFailure returns the minimum amount of failure in the current round.
+func (s State) MinFailure() int {
+	ri := s.Round()
+	if ri >= len(s.Rounds) {
+		return 1
+	}
+
+	return int(s.Rounds[ri].MinFailure)
+}
+
+// IsLastProposal returns a boolean value representing if declining the current proposal would lose the round.
+func (s State) IsLastProposal() bool {
+	ri := s.Round()
+	if ri >= len(s.Rounds) {
+		return false
+	}
+
+	return s.Proposal+1 >= int(s.Rounds[ri].Proposals)
+}
+
+// IsSpy returns a boolean value representing if the bot knows that the player is a spy.
+func (s State) IsSpy(id string) bool {
+	return s.Players[id].IsSpy()
+}
+
+// Suspicion returns the amount of failures in the finished missions that the player was assigned to.
+func (s State) Suspicion(id string) (n int) {
+	for _, r := range s.Results {
+		for _, m := range r.Missions {
+			if !m.IsAccepted() {
+				continue
+			}
+
+			for _, v := range m.Assignees {
+				if v == id {
+					n += int(r.Failure)
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// spies returns the amount of players in the team that the bot knows are spies.
+func (s State) spies(team []string) (n int) {
+	for _, id := range team {
+		if s.IsSpy(id) {
+			n++
+		}
+	}
+
+	return
+}
+
+// contains returns a boolean value representing if id is in the team.
+func contains(team []string, id string) bool {
+	for _, v := range team {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// others returns every player except the bot, the least suspicious first.
+func (s State) others() []string {
+	ids := []string{}
+	for _, id := range s.Seats {
+		if id != s.ID {
+			ids = append(ids, id)
+		}
+	}
+
+	rand.Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		return s.Suspicion(ids[i]) < s.Suspicion(ids[j])
+	})
+
+	return ids
+}
+
+// Strategy decides what a bot does in each phase.
+type Strategy interface {
+	// Choose returns the team that the bot proposes whenever it's the captain, the team must contain State.Size players.
+	Choose(s State) []string
+	// Vote returns true to accept the proposed team.
+	Vote(s State, team []string) bool
+	// Decide returns true for a successful mission, it only matters if the bot is a spy.
+	Decide(s State, team []string) bool
+}
+
+const (
+	// StrategyRandom is the name of Random.
+	StrategyRandom = "random"
+	// StrategyCautious is the name of Cautious.
+	StrategyCautious = "cautious"
+	// StrategyAggressive is the name of Aggressive.
+	StrategyAggressive = "aggressive"
+)
+
+// Strategies is a map containing every Strategy represented by its name.
+var Strategies = map[string]Strategy{
+	StrategyRandom:     Random{},
+	StrategyCautious:   Cautious{},
+	StrategyAggressive: Aggressive{},
+}
+
+// Random picks random teams, votes randomly and fails half of the missions as a spy.
+type Random struct{}
+
+func (Random) Choose(s State) []string {
+	ids := append([]string{}, s.Seats...)
+	rand.Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+
+	return ids[:s.Size()]
+}
+
+func (Random) Vote(s State, team []string) bool {
+	return rand.Intn(2) == 0
+}
+
+func (Random) Decide(s State, team []string) bool {
+	if !s.Type().IsSpy() {
+		return true
+	}
+
+	return rand.Intn(2) == 0
+}
+
+// Cautious plays it safe. As resistance it avoids suspicious players, as a spy it tries not to get caught.
+type Cautious struct{}
+
+func (Cautious) Choose(s State) []string {
+	team := []string{s.ID}
+	spy := s.Type().IsSpy()
+
+	for _, id := range s.others() {
+		if len(team) == s.Size() {
+			break
+		}
+
+		// a team with a single spy looks less suspicious
+		if s.IsSpy(id) {
+			continue
+		}
+
+		team = append(team, id)
+	}
+
+	// there weren't enough players that aren't spies
+	for _, id := range s.others() {
+		if len(team) == s.Size() {
+			break
+		}
+
+		if !contains(team, id) && (spy || s.IsSpy(id)) {
+			team = append(team, id)
+		}
+	}
+
+	return team
+}
+
+func (Cautious) Vote(s State, team []string) bool {
+	// declining the last proposal loses the round
+	if s.IsLastProposal() {
+		return true
+	}
+
+	if s.Type().IsSpy() {
+		return s.spies(team) > 0
+	}
+
+	if s.spies(team) > 0 {
+		return false
+	}
+
+	for _, id := range team {
+		if id != s.ID && s.Suspicion(id) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (Cautious) Decide(s State, team []string) bool {
+	if !s.Type().IsSpy() {
+		return true
+	}
+
+	// only fail when the other spies can't, so the spies don't reveal themselves
+	return s.spies(team) > s.MinFailure()
+}
+
+// Aggressive takes risks. As resistance it only trusts itself, as a spy it fails every mission.
+type Aggressive struct{}
+
+func (Aggressive) Choose(s State) []string {
+	team := []string{s.ID}
+
+	// spies bring enough spies to fail the mission
+	if s.Type().IsSpy() {
+		for _, id := range s.others() {
+			if len(team) == s.MinFailure() || len(team) == s.Size() {
+				break
+			}
+
+			if s.IsSpy(id) {
+				team = append(team, id)
+			}
+		}
+	}
+
+	for _, id := range s.others() {
+		if len(team) == s.Size() {
+			break
+		}
+
+		if !contains(team, id) && !s.IsSpy(id) {
+			team = append(team, id)
+		}
+	}
+
+	for _, id := range s.others() {
+		if len(team) == s.Size() {
+			break
+		}
+
+		if !contains(team, id) {
+			team = append(team, id)
+		}
+	}
+
+	return team
+}
+
+func (Aggressive) Vote(s State, team []string) bool {
+	if s.Type().IsSpy() {
+		return s.spies(team) > 0
+	}
+
+	return contains(team, s.ID) || s.IsLastProposal()
+}
+
+func (Aggressive) Decide(s State, team []string) bool {
+	return !s.Type().IsSpy()
+}
@@ -60,5 +60,16 @@ func (c Config) Validate() error {
 		validate = validator.New()
 	}
 
-	return validate.Struct(c)
+	err := validate.Struct(c)
+	if err != nil {
+		return err
+	}
+
+	// the lobby config contains the rulesets
+	err = c.Lobby.Validate()
+	if err != nil {
+		return fmt.Errorf("c.Lobby.Validate: %w", err)
+	}
+
+	return nil
 }
@@ -132,10 +132,11 @@ func (c *connStruct) AddCommand(group string, msgstrct MessageStruct) {
 
 func (c *connStruct) ExecuteCommand(group, name string, bytes []byte) error {
 	c.mtx.Lock()
-	defer c.mtx.Unlock()
 	g, ok := c.cmd[group]
 	if ok {
 		cmd, ok := g[name]
+		// unlock before executing, so the command can add or remove commands
+		c.mtx.Unlock()
 		if ok {
 			val := cmd(c.log, bytes)
 			return val
@@ -143,6 +144,7 @@ func (c *connStruct) ExecuteCommand(group, name string, bytes []byte) error {
 
 		return fmt.Errorf("c.cmd[name].(bool) != true")
 	}
+	c.mtx.Unlock()
 
 	return fmt.Errorf("c.cmd[group].(bool) != true")
 }
@@ -274,23 +276,27 @@ func (c *connStruct) read() {
 			messagejson := &MessageRecv{}
 			json.Unmarshal(bytes, messagejson)
 			if len(messagejson.Group) > 0 {
+				var callback MessageCallback
+
 				c.mtx.Lock()
 				strct, ok := c.cmd[messagejson.Group]
 				if ok {
 					if len(messagejson.Name) > 0 {
-						callback, ok := strct[messagejson.Name]
-						if ok {
-							err = callback(c.log, messagejson.Body)
-							if err != nil {
-								fullname := fmt.Sprintf("%s.%s", messagejson.Group, messagejson.Name)
-								c.log.Debug("c.MessageRecv: %s %v", fullname, err)
-							}
-						}
+						callback = strct[messagejson.Name]
 					}
 				} else {
 					c.log.Warn("!c.cmd.(bool): %+v", strct)
 				}
 				c.mtx.Unlock()
+
+				// the callback is executed without the lock, so it can add or remove commands
+				if callback != nil {
+					err = callback(c.log, messagejson.Body)
+					if err != nil {
+						fullname := fmt.Sprintf("%s.%s", messagejson.Group, messagejson.Name)
+						c.log.Debug("c.MessageRecv: %s %v", fullname, err)
+					}
+				}
 			}
 		} else if opcode == ws.OpClose {
 			return
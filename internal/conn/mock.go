@@ -128,11 +128,12 @@ func (m *mock) AddCommand(group string, msgstrct MessageStruct) {
 }
 
 func (m *mock) ExecuteCommand(group, name string, body []byte) error {
-	defer m.mtx.Unlock()
 	m.mtx.Lock()
 	g, ok := m.cmd[group]
 	if ok {
 		cmd, ok := g[name]
+		// unlock before executing, so the command can add or remove commands
+		m.mtx.Unlock()
 		if ok {
 			val := cmd(m.log, body)
 			return val
@@ -140,6 +141,7 @@ func (m *mock) ExecuteCommand(group, name string, body []byte) error {
 
 		return fmt.Errorf("c.cmd[name].(bool) != true")
 	}
+	m.mtx.Unlock()
 
 	return fmt.Errorf("c.cmd[group].(bool) != true")
 }
@@ -182,12 +184,13 @@ func (m *mock) WriteBytes(body []byte) {
 
 func (m *mock) GetDone() chan bool {
 	var done = make(chan bool)
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
 	if m.done == nil {
 		m.done = []chan bool{}
 	}
 
-	m.mtx.Lock()
-	defer m.mtx.Unlock()
 	m.done = append(m.done, done)
 	return done
 }
@@ -2,6 +2,7 @@ package conn
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/toms1441/resistance-server/internal/client"
 	"github.com/toms1441/resistance-server/internal/logger"
@@ -51,4 +52,6 @@ type MessageSend struct {
 	Group string      `json:"group"`
 	Name  string      `json:"name"`
 	Body  interface{} `json:"body"`
+	// Deadline is when the phase that the message started ends, it's only set in phase messages that can time out.
+	Deadline *time.Time `json:"deadline,omitempty"`
 }
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+// runAssassination is a blocking method that returns whenever the assassination phase is finished.
+// if OptionAssassin is set only the assassin can assassinate, else any spy can.
+func (g *Game) runAssassination() {
+
+	g.log.Debug("start of runAssassination()")
+
+	assassination := &Assassination{
+		Assassins: []string{},
+		Targets:   []string{},
+	}
+
+	for _, id := range g.Seats {
+		p, ok := g.Players[id]
+		if !ok || !p.IsValid() {
+			continue
+		}
+
+		if p.Type.IsSpy() {
+			if !g.Option.Has(OptionAssassin) || p.Type == PlayerTypeAssassin {
+				assassination.Assassins = append(assassination.Assassins, id)
+			}
+		} else {
+			assassination.Targets = append(assassination.Targets, id)
+		}
+	}
+
+	g.mtx.Lock()
+	g.Assassination = assassination
+	g.mtx.Unlock()
+
+	ctx, cancel, deadline := newPhase(g.timers.Assassinate)
+	g.startAssassinationPhase(cancel)
+
+	// inform the players that we're in the assassination phase
+	// and give em who can assassinate and who can be assassinated
+	g.Broadcast(conn.MessageSend{
+		Group:    "game",
+		Name:     "assassinate",
+		Body:     assassination,
+		Deadline: deadline,
+	})
+
+	<-ctx.Done()
+	for _, id := range assassination.Assassins {
+		p, ok := g.Players[id]
+		if ok {
+			p.RemoveCommandsByNames("game", "assassinate")
+		}
+	}
+
+	g.log.Debug("g.Assassination.Target: %s", g.Assassination.Target)
+}
+
+// startAssassinationPhase is a method for adding the game.assassinate command to the assassins.
+// the first assassin to pick a valid target decides for the whole team.
+func (g *Game) startAssassinationPhase(cancel context.CancelFunc) {
+
+	for _, id := range g.Assassination.Assassins {
+		p, ok := g.Players[id]
+		if !ok {
+			continue
+		}
+
+		p.AddCommand("game", conn.MessageStruct{
+			"assassinate": func(log logger.Logger, body []byte) error {
+				var target string
+
+				err := json.Unmarshal(body, &target)
+				if err != nil {
+					return fmt.Errorf("json.Unmarshal: %v", err)
+				}
+
+				g.mtx.Lock()
+				defer g.mtx.Unlock()
+
+				if len(g.Assassination.Target) > 0 {
+					return fmt.Errorf("player id: %s has already been assassinated", g.Assassination.Target)
+				}
+
+				for _, v := range g.Assassination.Targets {
+					if v == target {
+						g.Assassination.Target = target
+						g.record(Event{
+							Type:   EventAssassination,
+							Player: p.GetClient().ID,
+							Target: target,
+						})
+						cancel()
+
+						return nil
+					}
+				}
+
+				return ErrInvalidPlayer
+			},
+		})
+	}
+}
@@ -2,53 +2,112 @@ package game
 
 import (
 	"encoding/json"
-	"errors"
-	"math/rand"
-	"time"
+	"fmt"
+	"sort"
 
 	"github.com/toms1441/resistance-server/internal/conn"
 	"github.com/toms1441/resistance-server/internal/logger"
 )
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
+// NewGame returns a new pointer to Game struct by providing a slice of clients, the type of game and the game options.
+// It uses DefaultRuleset.
+func NewGame(clients map[string]conn.Conn, t uint8, o Option, settings ...Setting) (*Game, error) {
+	return NewGameWithRuleset(clients, t, o, DefaultRuleset, settings...)
 }
 
-// NewGame returns a new pointer to Game struct by providing a slice of clients, the type of game and the game options.
-func NewGame(clients map[string]conn.Conn, t uint8, o Option) (*Game, error) {
+// NewGameWithRuleset is the same as NewGame, only the rules are taken from rs.
+func NewGameWithRuleset(clients map[string]conn.Conn, t uint8, o Option, rs Ruleset, settings ...Setting) (*Game, error) {
 
 	g := &Game{
+		ID:     newID(),
 		log:    logger.NullLogger(),
 		Type:   Type(t),
 		Option: o,
+		timers: TimerPresets[TimersStandard],
 	}
 
-	if o.Has(OptionPercival) && t != TypeAvalon.Common() {
-		return nil, errors.New("percival must be equipped with type avalon")
+	if err := o.Validate(t); err != nil {
+		return nil, err
 	}
 
 	g.Players = map[string]Player{}
-	g.playerids = []string{}
+	g.Seats = []string{}
 
 	if len(clients) < 5 || len(clients) > 10 {
 		return nil, ErrInvalidClients
 	}
 
+	rules, ok := rs[len(clients)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrRuleset, len(clients))
+	}
+
+	if err := rules.Validate(len(clients)); err != nil {
+		return nil, fmt.Errorf("rules.Validate: %w", err)
+	}
+	g.Rules = rules
+
+	// assassin, mordred, oberon and the evil lancelot take the place of a spy
+	spyroles := 0
+	for _, v := range []Option{OptionAssassin, OptionMordred, OptionOberon, OptionLancelot} {
+		if o.Has(v) {
+			spyroles++
+		}
+	}
+
+	// so do the spy chief and the spy hunter
+	if t == TypeHunter.Common() {
+		spyroles += 2
+	}
+
+	if spyroles > rules.Spies {
+		return nil, ErrSpyRoles
+	}
+
+	for _, v := range settings {
+		v(g)
+	}
+	g.newRand()
+
 	for _, v := range clients {
 		p := newPlayer(v)
 		g.Players[v.GetClient().ID] = p
-		g.playerids = append(g.playerids, v.GetClient().ID)
+		g.Seats = append(g.Seats, v.GetClient().ID)
 	}
 
-	playerLen := len(g.Players)
-	if playerLen > 8 {
-		playerLen = 8
+	// map order is random, so the seats are sorted before being shuffled by the seed
+	sort.Strings(g.Seats)
+	g.seatRand.Shuffle(len(g.Seats), func(i, j int) {
+		g.Seats[i], g.Seats[j] = g.Seats[j], g.Seats[i]
+	})
+
+	if g.order != nil {
+		if len(g.order) != len(g.Players) {
+			return nil, ErrSeats
+		}
+
+		exists := map[string]bool{}
+		for _, id := range g.order {
+			if _, ok := g.Players[id]; !ok || exists[id] {
+				return nil, ErrSeats
+			}
+
+			exists[id] = true
+		}
+
+		g.Seats = append([]string{}, g.order...)
 	}
 
-	g.Rounds = RoundDefaults[playerLen]
+	g.Rounds = rules.Rounds()
 
 	g.assignRoles()
 
+	roles := map[string]PlayerType{}
+	for id, p := range g.Players {
+		roles[id] = p.Type
+	}
+	g.record(Event{Type: EventRoles, Roles: roles})
+
 	return g, nil
 }
 
@@ -59,6 +118,33 @@ func (g *Game) SetLogger(log logger.Logger) {
 
 // getStatus returns the status of the game. if it's finished or not.
 func (g *Game) getStatus() Status {
+	status := g.getRoundsStatus()
+
+	if status == StatusDefault {
+		return status
+	}
+
+	found := map[PlayerType]bool{}
+	for _, v := range g.Hunts {
+		p, ok := g.Players[v.Target]
+		if ok && p.Type.IsChief() {
+			found[p.Type] = true
+		}
+	}
+
+	// the hunter of the losing team gets one last chance to win, by finding the chief of the winning team.
+	// the hunter of the winning team keeps the win by finding the chief of the losing team as well
+	if status == StatusWon && found[PlayerTypeChiefResistance] && !found[PlayerTypeChiefSpy] {
+		return StatusLost
+	} else if status == StatusLost && found[PlayerTypeChiefSpy] && !found[PlayerTypeChiefResistance] {
+		return StatusWon
+	}
+
+	return status
+}
+
+// getRoundsStatus returns the status of the game without the hunt.
+func (g *Game) getRoundsStatus() Status {
 
 	resistance := 0
 	spies := 0
@@ -68,8 +154,8 @@ func (g *Game) getStatus() Status {
 		concul := v.GetConculsion()
 
 		if concul == StatusLost {
-			// we failed 5 rounds, so the game is over
-			if !v.Missions[4].IsAccepted() {
+			// every proposed mission got declined, so the game is over
+			if v.Proposals > 0 && v.Missions[v.Proposals-1].IsDeclined() {
 				return StatusLost
 			}
 
@@ -82,26 +168,55 @@ func (g *Game) getStatus() Status {
 	if spies == 3 {
 		return StatusLost
 	} else if resistance == 3 {
+		// the spies get one last chance to win, by assassinating merlin
+		if g.Assassination != nil {
+			p, ok := g.Players[g.Assassination.Target]
+			if ok && p.Type == PlayerTypeMerlin {
+				return StatusLost
+			}
+		}
+
 		return StatusWon
 	}
 
 	return StatusDefault
 }
 
+// getReason returns the reason of the game's status.
+func (g *Game) getReason() Reason {
+	status := g.getStatus()
+	if status == StatusDefault {
+		return ReasonDefault
+	}
+
+	// the hunt changed the outcome of the rounds
+	if status != g.getRoundsStatus() {
+		return ReasonHunt
+	}
+
+	if g.Assassination != nil {
+		p, ok := g.Players[g.Assassination.Target]
+		if ok && p.Type == PlayerTypeMerlin {
+			return ReasonAssassination
+		}
+	}
+
+	for _, v := range g.Rounds {
+		if v.GetConculsion() == StatusLost && v.Proposals > 0 && v.Missions[v.Proposals-1].IsDeclined() {
+			return ReasonProposals
+		}
+	}
+
+	return ReasonMissions
+}
+
 // assignRoles assigns the roles for the players.
 func (g *Game) assignRoles() {
 
-	amount := len(g.playerids)
-	playerIndex := g.playerids
+	// a copy, deleteIndex would otherwise shuffle the seats
+	playerIndex := append([]string{}, g.Seats...)
 
-	spies := 0
-	if amount >= 5 && amount <= 6 {
-		spies = 2
-	} else if amount >= 7 && amount <= 9 {
-		spies = 3
-	} else if amount == 10 {
-		spies = 4
-	}
+	spies := g.Rules.Spies
 
 	// deleteIndex is a helper function to delete an index from the array.
 	// it's crucial in this operation, Example:
@@ -116,21 +231,62 @@ func (g *Game) assignRoles() {
 		return arr[:len(arr)-1]
 	}
 
+	spyIndex := []string{}
 	for i := 0; i < spies; i++ {
 		intn := len(playerIndex)
 
-		spyindex := rand.Intn(intn)
+		spyindex := g.rand.IntN(intn)
 		// get the random spy index from the amount of players
 		p, ok := g.Players[playerIndex[spyindex]]
 		if ok {
 			p.Type = PlayerTypeSpy
 			// assign that player to be a spy
 			g.Players[playerIndex[spyindex]] = p
+			spyIndex = append(spyIndex, playerIndex[spyindex])
 			// then delete that id from the string array
 			playerIndex = deleteIndex(playerIndex, spyindex)
 		}
 	}
 
+	// pickSpy turns a random spy into ptype, so the amount of spies stays the same
+	pickSpy := func(ptype PlayerType) {
+		intn := len(spyIndex)
+		if intn <= 0 {
+			g.log.Danger("pickSpy: no spies left for %d", ptype)
+			return
+		}
+
+		spyindex := g.rand.IntN(intn)
+		p, ok := g.Players[spyIndex[spyindex]]
+		if ok {
+			p.Type = ptype
+
+			g.Players[spyIndex[spyindex]] = p
+			spyIndex = deleteIndex(spyIndex, spyindex)
+		}
+	}
+
+	if g.Option.Has(OptionAssassin) {
+		pickSpy(PlayerTypeAssassin)
+	}
+
+	if g.Option.Has(OptionMordred) {
+		pickSpy(PlayerTypeMordred)
+	}
+
+	if g.Option.Has(OptionOberon) {
+		pickSpy(PlayerTypeOberon)
+	}
+
+	if g.Option.Has(OptionLancelot) {
+		pickSpy(PlayerTypeLancelotEvil)
+	}
+
+	if g.Type == TypeHunter {
+		pickSpy(PlayerTypeChiefSpy)
+		pickSpy(PlayerTypeHunterSpy)
+	}
+
 	for _, v := range playerIndex {
 		p, ok := g.Players[v]
 		if ok {
@@ -145,7 +301,7 @@ func (g *Game) assignRoles() {
 			return
 		}
 
-		merlin := rand.Intn(intn)
+		merlin := g.rand.IntN(intn)
 		p, ok := g.Players[playerIndex[merlin]]
 		if ok {
 			p.Type = PlayerTypeMerlin
@@ -155,6 +311,40 @@ func (g *Game) assignRoles() {
 		}
 	}
 
+	if g.Type == TypeHunter {
+		for _, ptype := range []PlayerType{PlayerTypeChiefResistance, PlayerTypeHunterResistance} {
+			intn := len(playerIndex)
+			if intn <= 0 {
+				return
+			}
+
+			index := g.rand.IntN(intn)
+			p, ok := g.Players[playerIndex[index]]
+			if ok {
+				p.Type = ptype
+
+				g.Players[playerIndex[index]] = p
+				playerIndex = deleteIndex(playerIndex, index)
+			}
+		}
+	}
+
+	if g.Option.Has(OptionLancelot) {
+		intn := len(playerIndex)
+		if intn <= 0 {
+			return
+		}
+
+		lancelot := g.rand.IntN(intn)
+		p, ok := g.Players[playerIndex[lancelot]]
+		if ok {
+			p.Type = PlayerTypeLancelotGood
+
+			g.Players[playerIndex[lancelot]] = p
+			playerIndex = deleteIndex(playerIndex, lancelot)
+		}
+	}
+
 	op := g.Option
 
 	percival := -1
@@ -164,7 +354,7 @@ func (g *Game) assignRoles() {
 			return
 		}
 
-		percival = rand.Intn(intn)
+		percival = g.rand.IntN(intn)
 		p, ok := g.Players[playerIndex[percival]]
 		if ok {
 			p.Type = PlayerTypePercival
@@ -181,7 +371,7 @@ func (g *Game) assignRoles() {
 		}
 
 		if percival == -1 {
-			percival = rand.Intn(intn)
+			percival = g.rand.IntN(intn)
 			p, ok := g.Players[playerIndex[percival]]
 			if ok {
 				p.Type = PlayerTypePercival
@@ -196,7 +386,7 @@ func (g *Game) assignRoles() {
 			return
 		}
 
-		morgana := rand.Intn(len(playerIndex))
+		morgana := g.rand.IntN(len(playerIndex))
 
 		p, ok := g.Players[playerIndex[morgana]]
 		if ok {
@@ -216,22 +406,74 @@ func (g *Game) Broadcast(ms conn.MessageSend) {
 		return
 	}
 
-	for _, v := range g.Players {
+	for _, v := range g.players() {
 		// we need to check because most of the slice slots are empty
 		if v.IsValid() {
 			v.Conn.WriteBytes(bytes)
 		}
 	}
+	g.broadcastSpectators(bytes)
 	g.log.Debug("Sent Message: %s.%s", ms.Group, ms.Name)
 }
 
+// players returns a copy of the players, the loyalty cards switch their types while they're being sent to.
+func (g *Game) players() map[string]Player {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	players := make(map[string]Player, len(g.Players))
+	for k, v := range g.Players {
+		players[k] = v
+	}
+
+	return players
+}
+
 // Run runs the game and sets chan<- Status when the game is done.
 func (g *Game) Run(s chan<- Status) {
 
 	defer func(s chan<- Status) {
-		s <- g.getStatus()
+		end := End{
+			Status:  g.getStatus(),
+			Reason:  g.getReason(),
+			Winners: g.GetWinners(),
+			Players: map[string]PlayerType{},
+		}
+
+		for id, p := range g.Players {
+			end.Players[id] = p.Type
+		}
+
+		g.record(Event{
+			Type:    EventEnd,
+			Status:  end.Status,
+			Reason:  end.Reason,
+			Players: end.Winners,
+		})
+
+		// the game is over, so every role gets revealed
+		g.Broadcast(conn.MessageSend{
+			Group: "game",
+			Name:  "end",
+			Body:  end,
+		})
+		g.closeSpectators()
+
+		s <- end.Status
 	}(s)
 
+	if g.Option.Has(OptionLady) {
+		g.newLady()
+	}
+
+	if g.Option.Has(OptionLancelot) {
+		g.newLoyalty()
+	}
+
+	if g.Type == TypeOriginal {
+		g.newPlot()
+	}
+
 	g.Send()
 	for ri := 0; ri < 5; ri++ {
 		// g.runRound is a blocking method that returns whenever the round is finished
@@ -239,18 +481,123 @@ func (g *Game) Run(s chan<- Status) {
 		if g.runRound(ri) {
 			break
 		}
+
+		// the lady of the lake is used after the 2nd, 3rd and 4th round
+		if g.Lady != nil && ri >= 1 && ri <= 3 {
+			g.runLady(ri)
+		}
+
+		// so is the investigation in hunter, as long as the game isn't over
+		if g.Type == TypeHunter && ri >= 1 && ri <= 3 && g.getStatus() == StatusDefault {
+			g.runInvestigation(ri)
+		}
+	}
+
+	// in avalon, the resistance winning 3 rounds isn't the end of the game
+	if g.Type == TypeAvalon && g.getStatus() == StatusWon {
+		g.runAssassination()
+	}
+
+	// in hunter, neither is winning 3 rounds
+	if g.Type == TypeHunter && g.getStatus() != StatusDefault {
+		g.runHunt()
 	}
 
 }
 
+// GetWinners returns the ids of the players that won the game, by their final allegiance.
+// it's empty if the game isn't finished.
+func (g *Game) GetWinners() []string {
+	status := g.getStatus()
+
+	ids := []string{}
+	if status == StatusDefault {
+		return ids
+	}
+
+	for _, id := range g.Seats {
+		p, ok := g.Players[id]
+		if !ok {
+			continue
+		}
+
+		// the lancelots might've switched allegiance, so the final player type is used
+		if p.Type.IsSpy() == (status == StatusLost) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// isRevealed returns a boolean value representing if the loyalty of target has been revealed to viewer.
+func (g *Game) isRevealed(viewer, target string) bool {
+	if g.Lady != nil {
+		for _, check := range g.Lady.History {
+			if check.Holder == viewer && check.Target == target {
+				return true
+			}
+		}
+	}
+
+	if g.Plot != nil {
+		for _, play := range g.Plot.History {
+			if play.Card == PlotCardOverheardConversation && play.Player == viewer && play.Target == target {
+				return true
+			} else if play.Card == PlotCardOpenUp && play.Target == viewer && play.Player == target {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // Send sends the game information to all the players
 func (g *Game) Send() {
+	for _, v := range g.players() {
+		if v.IsValid() {
+			g.sendPlayer(v)
+		}
+	}
+	g.sendSpectators()
+}
+
+// sendPlayer sends the game information to a single player, masked for that player.
+func (g *Game) sendPlayer(p Player) {
+	p.Conn.WriteMessage(conn.MessageSend{
+		Group: "game",
+		Name:  "get",
+		Body:  g.mask(p.GetClient().ID, p.Type),
+	})
+}
 
-	maskGame := Game{}
-	maskGame = *g
+// view returns the game with the players, g.mtx needs to be locked before calling it.
+func (g *Game) view(players map[string]Player) view {
+	return view{
+		ID:             g.ID,
+		Type:           g.Type,
+		Rounds:         g.Rounds,
+		Option:         g.Option,
+		Status:         g.Status,
+		Players:        players,
+		Rules:          g.Rules,
+		Seats:          g.Seats,
+		Assassination:  g.Assassination,
+		Lady:           g.Lady,
+		Loyalty:        g.Loyalty,
+		Plot:           g.Plot.copy(),
+		Investigations: append([]Investigation{}, g.Investigations...),
+		Hunts:          append([]Hunt{}, g.Hunts...),
+	}
+}
 
-	maskPlayers := func(p Player) (arr map[string]Player) {
-		id := p.GetClient().ID
+// mask returns the game as seen by the player with the id and the player type.
+func (g *Game) mask(id string, ptype PlayerType) view {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	maskPlayers := func() (arr map[string]Player) {
 		arr = map[string]Player{}
 
 		for k, v := range g.Players {
@@ -261,24 +608,33 @@ func (g *Game) Send() {
 
 			// only change the type if the current player != player in loop
 			if id != k {
-				if p.Type == PlayerTypeResistance {
+				if ptype == PlayerTypeResistance || ptype == PlayerTypeLancelotGood || ptype == PlayerTypeChiefResistance || ptype == PlayerTypeHunterResistance {
 					// if the original player is resistance
 					// then mask every player
 					v.Type = PlayerTypeResistance
-				} else if p.Type == PlayerTypeSpy {
+				} else if ptype == PlayerTypeSpy || ptype == PlayerTypeAssassin || ptype == PlayerTypeMordred || ptype == PlayerTypeLancelotEvil || ptype == PlayerTypeChiefSpy || ptype == PlayerTypeHunterSpy {
 					// if the original player is a spy
-					// then mask merlin and percival
+					// then mask merlin, percival and the resistance chief and hunter
 					// so show resistance and morgana and fellow spies :)
-					if v.Type == PlayerTypeMerlin || v.Type == PlayerTypePercival {
+					// oberon is unknown to the rest of the spies
+					if !v.Type.IsSpy() || v.Type == PlayerTypeOberon {
 						v.Type = PlayerTypeResistance
 					}
-				} else if p.Type == PlayerTypeMerlin {
+				} else if ptype == PlayerTypeOberon {
+					// if the original player is oberon
+					// then mask every player, oberon doesn't know the rest of the spies
+					v.Type = PlayerTypeResistance
+				} else if ptype == PlayerTypeMerlin {
 					// if the original player is merlin
 					// then mask percival and morgana
-					if v.Type == PlayerTypePercival || v.Type == PlayerTypeMorgana {
+					// mordred is hidden from merlin
+					if v.Type == PlayerTypePercival || v.Type == PlayerTypeMorgana || v.Type == PlayerTypeMordred || v.Type == PlayerTypeLancelotGood {
 						v.Type = PlayerTypeResistance
+					} else if v.Type == PlayerTypeAssassin || v.Type == PlayerTypeOberon || v.Type == PlayerTypeLancelotEvil {
+						// merlin knows who the spies are, not what their roles are
+						v.Type = PlayerTypeSpy
 					}
-				} else if p.Type == PlayerTypePercival {
+				} else if ptype == PlayerTypePercival {
 					// if the original player is percival
 					// then reveal merlin and morgana as possible spies
 					// and mask all other players
@@ -287,15 +643,22 @@ func (g *Game) Send() {
 					} else if v.Type != PlayerTypeMerlin {
 						v.Type = PlayerTypeResistance
 					}
-				} else if p.Type == PlayerTypeMorgana {
+				} else if ptype == PlayerTypeMorgana {
 					// if the original player is morgana
 					// then hide everybody except spies
-					if v.Type != PlayerTypeSpy {
+					if !v.Type.IsSpy() || v.Type == PlayerTypeOberon {
 						v.Type = PlayerTypeResistance
 					}
 				}
 			}
 
+			// the lady of the lake and plot cards reveal the loyalty of a player, only to one player
+			if id != k && g.isRevealed(id, k) {
+				if g.Players[k].Type.IsSpy() && !v.Type.IsSpy() {
+					v.Type = PlayerTypeSpy
+				}
+			}
+
 			// add the newly modified player to arr
 			arr[v.GetClient().ID] = v
 		}
@@ -303,24 +666,35 @@ func (g *Game) Send() {
 		return arr
 	}
 
-	for _, v := range g.Players {
-		if v.IsValid() {
-			maskGame.Players = maskPlayers(v)
+	return g.view(maskPlayers())
+}
 
-			v.Conn.WriteMessage(conn.MessageSend{
-				Group: "game",
-				Name:  "get",
-				Body:  maskGame,
-			})
-		}
+// Rebind binds a returning client's new connection to their seat.
+// The commands of the phase that the player is in get added to the new connection, then the game gets sent to the player.
+func (g *Game) Rebind(c conn.Conn) error {
+	g.mtx.Lock()
+	p, ok := g.Players[c.GetClient().ID]
+	g.mtx.Unlock()
+	if !ok {
+		return ErrInvalidPlayer
+	}
+
+	s, ok := p.Conn.(*seat)
+	if !ok {
+		return ErrInvalidPlayer
 	}
 
+	s.rebind(c)
+	g.sendPlayer(p)
+
+	g.log.Debug("g.Rebind: %s", c.GetClient().ID)
+	return nil
 }
 
 func (g *Game) SetCaptain() {
 
 	index := -1
-	for k, v := range g.playerids {
+	for k, v := range g.Seats {
 		if v == g.captain {
 			index = k
 		}
@@ -328,16 +702,16 @@ func (g *Game) SetCaptain() {
 
 	if index == -1 {
 		// if we couldn't find the current captain
-		// set the captain index by a random client in the playerids
-		index = rand.Intn(len(g.playerids))
+		// set the captain index by a random client in the seats
+		index = g.rand.IntN(len(g.Seats))
 	} else {
 		// else just get the next player inline
 		index++
 		// if the index reached the last player, reset it
-		if index == len(g.playerids) {
+		if index == len(g.Seats) {
 			index = 0
 		}
 	}
 
-	g.captain = g.playerids[index]
+	g.captain = g.Seats[index]
 }
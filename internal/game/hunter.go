@@ -0,0 +1,243 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+// runInvestigation is a blocking method that returns whenever the captain investigated a player, or the time ran out.
+func (g *Game) runInvestigation(ri int) {
+
+	g.log.Debug("start of runInvestigation(%d)", ri)
+
+	// the captain of the last mission investigates
+	if len(g.captain) == 0 {
+		g.SetCaptain()
+	}
+
+	investigation := Investigation{
+		Round:        ri,
+		Investigator: g.captain,
+		Targets:      []string{},
+	}
+
+	for _, id := range g.Seats {
+		if id != g.captain {
+			investigation.Targets = append(investigation.Targets, id)
+		}
+	}
+
+	g.mtx.Lock()
+	g.Investigations = append(g.Investigations, investigation)
+	g.mtx.Unlock()
+
+	ctx, cancel, deadline := newPhase(g.timers.Investigate)
+	g.startInvestigationPhase(cancel)
+
+	// inform the players that the captain is investigating someone
+	g.Broadcast(conn.MessageSend{
+		Group:    "game",
+		Name:     "investigate",
+		Body:     investigation,
+		Deadline: deadline,
+	})
+
+	<-ctx.Done()
+	investigator, ok := g.Players[investigation.Investigator]
+	if !ok {
+		return
+	}
+
+	investigator.RemoveCommandsByNames("game", "investigate")
+
+	g.mtx.Lock()
+	target := g.Investigations[len(g.Investigations)-1].Target
+	g.mtx.Unlock()
+
+	p, ok := g.Players[target]
+	if !ok {
+		return
+	}
+
+	// only the investigator knows if the target is a chief or not
+	investigator.WriteMessage(conn.MessageSend{
+		Group: "game",
+		Name:  "investigation",
+		Body: InvestigationResult{
+			Target: target,
+			Chief:  p.Type.IsChief(),
+		},
+	})
+}
+
+// startInvestigationPhase is a method for adding the game.investigate command to the investigator.
+func (g *Game) startInvestigationPhase(cancel context.CancelFunc) {
+
+	index := len(g.Investigations) - 1
+	investigation := g.Investigations[index]
+
+	investigator, ok := g.Players[investigation.Investigator]
+	if !ok {
+		return
+	}
+
+	investigator.AddCommand("game", conn.MessageStruct{
+		"investigate": func(log logger.Logger, body []byte) error {
+			var target string
+
+			err := json.Unmarshal(body, &target)
+			if err != nil {
+				return fmt.Errorf("json.Unmarshal: %v", err)
+			}
+
+			g.mtx.Lock()
+			defer g.mtx.Unlock()
+
+			if len(g.Investigations[index].Target) > 0 {
+				return fmt.Errorf("player id: %s has already been investigated", g.Investigations[index].Target)
+			}
+
+			for _, v := range investigation.Targets {
+				if v == target {
+					g.Investigations[index].Target = target
+					g.record(Event{
+						Type:   EventInvestigation,
+						Round:  investigation.Round,
+						Player: investigation.Investigator,
+						Target: target,
+					})
+					cancel()
+
+					return nil
+				}
+			}
+
+			return ErrInvalidPlayer
+		},
+	})
+}
+
+// runHunt is a blocking method that returns whenever the hunt phase is finished.
+// the hunter of each team gets to accuse a player of the other team of being their chief, see getStatus for what the accusations do.
+func (g *Game) runHunt() {
+
+	g.log.Debug("start of runHunt()")
+
+	hunts := []Hunt{}
+	for _, hunter := range []PlayerType{PlayerTypeHunterSpy, PlayerTypeHunterResistance} {
+		// the spy hunter hunts the resistance chief, and the other way around
+		spy := hunter.IsSpy()
+
+		hunt := Hunt{
+			Targets: []string{},
+		}
+
+		for _, id := range g.Seats {
+			p, ok := g.Players[id]
+			if !ok || !p.IsValid() {
+				continue
+			}
+
+			if p.Type == hunter {
+				hunt.Hunter = id
+			} else if p.Type.IsSpy() != spy {
+				hunt.Targets = append(hunt.Targets, id)
+			}
+		}
+
+		if len(hunt.Hunter) == 0 {
+			g.log.Danger("runHunt: no hunter with type %d", hunter)
+			continue
+		}
+
+		hunts = append(hunts, hunt)
+	}
+
+	if len(hunts) == 0 {
+		return
+	}
+
+	g.mtx.Lock()
+	g.Hunts = append([]Hunt{}, hunts...)
+	g.mtx.Unlock()
+
+	ctx, cancel, deadline := newPhase(g.timers.Hunt)
+	for k := range hunts {
+		g.startHuntPhase(cancel, k)
+	}
+
+	// inform the players that we're in the hunt phase
+	// and give em who hunts and who can be accused
+	for _, hunt := range hunts {
+		g.Broadcast(conn.MessageSend{
+			Group:    "game",
+			Name:     "hunt",
+			Body:     hunt,
+			Deadline: deadline,
+		})
+	}
+
+	<-ctx.Done()
+	for _, hunt := range hunts {
+		g.Players[hunt.Hunter].RemoveCommandsByNames("game", "hunt")
+	}
+
+	g.mtx.Lock()
+	g.log.Debug("g.Hunts: %v", g.Hunts)
+	g.mtx.Unlock()
+}
+
+// startHuntPhase is a method for adding the game.hunt command to the hunter of g.Hunts[index].
+// the phase is cancelled once every hunter accused someone.
+func (g *Game) startHuntPhase(cancel context.CancelFunc, index int) {
+
+	p, ok := g.Players[g.Hunts[index].Hunter]
+	if !ok {
+		return
+	}
+
+	p.AddCommand("game", conn.MessageStruct{
+		"hunt": func(log logger.Logger, body []byte) error {
+			var target string
+
+			err := json.Unmarshal(body, &target)
+			if err != nil {
+				return fmt.Errorf("json.Unmarshal: %v", err)
+			}
+
+			g.mtx.Lock()
+			defer g.mtx.Unlock()
+
+			hunt := &g.Hunts[index]
+			if len(hunt.Target) > 0 {
+				return fmt.Errorf("player id: %s has already been accused", hunt.Target)
+			}
+
+			for _, v := range hunt.Targets {
+				if v == target {
+					hunt.Target = target
+					g.record(Event{
+						Type:   EventHunt,
+						Player: hunt.Hunter,
+						Target: target,
+					})
+
+					for _, h := range g.Hunts {
+						if len(h.Target) == 0 {
+							return nil
+						}
+					}
+
+					cancel()
+					return nil
+				}
+			}
+
+			return ErrInvalidPlayer
+		},
+	})
+}
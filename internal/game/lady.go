@@ -0,0 +1,131 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+// newLady gives the lady of the lake token to the player before the first captain.
+func (g *Game) newLady() {
+	if len(g.captain) == 0 {
+		g.SetCaptain()
+	}
+
+	index := 0
+	for k, v := range g.Seats {
+		if v == g.captain {
+			index = k
+		}
+	}
+
+	// the player before the captain, if the captain is the first player then it's the last player
+	index--
+	if index < 0 {
+		index = len(g.Seats) - 1
+	}
+
+	g.Lady = &Lady{
+		Holder:  g.Seats[index],
+		Targets: []string{},
+		History: []LadyCheck{},
+	}
+}
+
+// runLady is a blocking method that returns whenever the holder checked a player, or the time ran out.
+func (g *Game) runLady(ri int) {
+
+	g.log.Debug("start of runLady(%d)", ri)
+
+	// players that held the token can't be checked
+	held := map[string]bool{
+		g.Lady.Holder: true,
+	}
+	for _, v := range g.Lady.History {
+		held[v.Holder] = true
+	}
+
+	targets := []string{}
+	for _, id := range g.Seats {
+		if !held[id] {
+			targets = append(targets, id)
+		}
+	}
+
+	g.mtx.Lock()
+	g.Lady.Targets = targets
+	g.mtx.Unlock()
+
+	ctx, cancel, deadline := newPhase(g.timers.Lady)
+	g.startLadyPhase(cancel, ri)
+
+	holder := g.Players[g.Lady.Holder]
+
+	// inform the players that the holder is checking someone
+	g.Broadcast(conn.MessageSend{
+		Group:    "game",
+		Name:     "lady",
+		Body:     g.Lady,
+		Deadline: deadline,
+	})
+
+	<-ctx.Done()
+	holder.RemoveCommandsByNames("game", "lady")
+
+	// the result of the check is masked for everybody except the holder
+	g.Send()
+}
+
+// startLadyPhase is a method for adding the game.lady command to the token holder.
+func (g *Game) startLadyPhase(cancel context.CancelFunc, ri int) {
+
+	holder, ok := g.Players[g.Lady.Holder]
+	if !ok {
+		return
+	}
+
+	holder.AddCommand("game", conn.MessageStruct{
+		"lady": func(log logger.Logger, body []byte) error {
+			var target string
+
+			err := json.Unmarshal(body, &target)
+			if err != nil {
+				return fmt.Errorf("json.Unmarshal: %v", err)
+			}
+
+			g.mtx.Lock()
+			defer g.mtx.Unlock()
+
+			if g.Lady.Holder != holder.GetClient().ID {
+				return fmt.Errorf("player id: %s no longer holds the token", holder.GetClient().ID)
+			}
+
+			for _, v := range g.Lady.Targets {
+				if v == target {
+					g.Lady.History = append(g.Lady.History, LadyCheck{
+						Round:  ri,
+						Holder: g.Lady.Holder,
+						Target: target,
+					})
+					g.record(Event{
+						Type:   EventLady,
+						Round:  ri,
+						Player: g.Lady.Holder,
+						Target: target,
+					})
+					// the token passes to the player that got checked
+					g.Lady.Holder = target
+					g.Lady.Targets = []string{}
+
+					cancel()
+					return nil
+				}
+			}
+
+			return ErrInvalidPlayer
+		},
+	})
+}
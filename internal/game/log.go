@@ -0,0 +1,112 @@
+package game
+
+import (
+	"time"
+)
+
+const (
+	// EventRoles is recorded once the roles are assigned, Event.Roles contains every role.
+	EventRoles EventType = iota
+	// EventCaptain is recorded whenever the captain changes, Event.Player is the new captain.
+	EventCaptain
+	// EventTeam is recorded whenever a team is proposed, Event.Player is the captain and Event.Players is the team.
+	EventTeam
+	// EventVote is recorded for each vote, Event.Player is the voter and Event.Accept is the vote.
+	EventVote
+	// EventCard is recorded for each mission card, Event.Player is the assignee and Event.Success is the card.
+	EventCard
+	// EventRound is recorded whenever a round is finished, Event.Status is the conclusion of the round and Event.Failure is the amount of failure cards.
+	EventRound
+	// EventLoyalty is recorded whenever a loyalty card is drawn, Event.Switch is the card.
+	EventLoyalty
+	// EventPlot is recorded whenever a plot card is given or played, Event.Card is the card.
+	// Event.Player is the player that played it, or the captain that gave it. Event.Target is the target of the card, or the player that got it.
+	EventPlot
+	// EventLady is recorded whenever the lady of the lake is used, Event.Player is the holder and Event.Target is the player that got checked.
+	EventLady
+	// EventInvestigation is recorded whenever a player is investigated, Event.Player is the investigator and Event.Target is the player that got investigated.
+	EventInvestigation
+	// EventAssassination is recorded whenever a player is assassinated, Event.Player is the assassin and Event.Target is the player that got assassinated.
+	EventAssassination
+	// EventHunt is recorded whenever a player is accused of being a chief, Event.Player is the hunter and Event.Target is the player that got accused.
+	EventHunt
+	// EventEnd is recorded once the game is finished, Event.Status is the status of the game, Event.Reason is the reason and Event.Players are the winners.
+	EventEnd
+)
+
+var eventTypeStrings = map[EventType]string{
+	EventRoles:         "Roles",
+	EventCaptain:       "Captain",
+	EventTeam:          "Team",
+	EventVote:          "Vote",
+	EventCard:          "Card",
+	EventRound:         "Round",
+	EventLoyalty:       "Loyalty",
+	EventPlot:          "Plot",
+	EventLady:          "Lady",
+	EventInvestigation: "Investigation",
+	EventAssassination: "Assassination",
+	EventHunt:          "Hunt",
+	EventEnd:           "End",
+}
+
+// EventType is a uint8 representation of the event type.
+type EventType uint8
+
+func (et EventType) String() string {
+	val, ok := eventTypeStrings[et]
+	if !ok {
+		return ""
+	}
+
+	return val
+}
+
+// Event is a single state change in the game. Only the fields that are documented by the event type get set.
+type Event struct {
+	// the position of the event in the log, starting from zero
+	Index int       `json:"index"`
+	Time  time.Time `json:"time"`
+	Type  EventType `json:"type"`
+	// the round and mission in which the event happened, zero for the events that aren't a part of a round
+	Round   int `json:"round"`
+	Mission int `json:"mission"`
+	// by id
+	Player string `json:"player,omitempty"`
+	// by id
+	Players []string `json:"players,omitempty"`
+	// by id
+	Target string `json:"target,omitempty"`
+	// by id
+	Roles   map[string]PlayerType `json:"roles,omitempty"`
+	Accept  bool                  `json:"accept,omitempty"`
+	Success bool                  `json:"success,omitempty"`
+	Switch  bool                  `json:"switch,omitempty"`
+	Card    PlotCard              `json:"card,omitempty"`
+	Status  Status                `json:"status,omitempty"`
+	Failure uint8                 `json:"failure,omitempty"`
+	Reason  Reason                `json:"reason,omitempty"`
+}
+
+// record appends the event to the log. It has its own mutex, so it can be called with or without g.mtx being locked.
+func (g *Game) record(e Event) {
+	g.emtx.Lock()
+	defer g.emtx.Unlock()
+
+	e.Index = len(g.events)
+	e.Time = time.Now()
+
+	g.events = append(g.events, e)
+}
+
+// Log returns a copy of every event in the game, in the order they happened.
+// The log contains the roles and every mission card, so it should never be sent to the players while the game is running.
+func (g *Game) Log() []Event {
+	g.emtx.Lock()
+	defer g.emtx.Unlock()
+
+	events := make([]Event, len(g.events))
+	copy(events, g.events)
+
+	return events
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"github.com/toms1441/resistance-server/internal/conn"
+)
+
+// newLoyalty shuffles the loyalty deck, it's made out of 5 no change cards and 2 switch cards.
+func (g *Game) newLoyalty() {
+	deck := []bool{false, false, false, false, false, true, true}
+	g.rand.Shuffle(len(deck), func(i, j int) {
+		deck[i], deck[j] = deck[j], deck[i]
+	})
+
+	g.Loyalty = &Loyalty{
+		Drawn: []bool{},
+		deck:  deck,
+	}
+}
+
+// runLoyalty draws a card from the loyalty deck and reveals it to everybody.
+// if it's a switch card, the lancelots switch allegiance.
+func (g *Game) runLoyalty(ri int) {
+	if len(g.Loyalty.deck) == 0 {
+		return
+	}
+
+	g.mtx.Lock()
+	card := g.Loyalty.deck[0]
+	g.Loyalty.deck = g.Loyalty.deck[1:]
+	g.Loyalty.Drawn = append(g.Loyalty.Drawn, card)
+	g.record(Event{
+		Type:   EventLoyalty,
+		Round:  ri,
+		Switch: card,
+	})
+
+	if card {
+		for k, v := range g.Players {
+			if v.Type == PlayerTypeLancelotGood {
+				v.Type = PlayerTypeLancelotEvil
+			} else if v.Type == PlayerTypeLancelotEvil {
+				v.Type = PlayerTypeLancelotGood
+			}
+
+			g.Players[k] = v
+		}
+	}
+
+	loyalty := &Loyalty{Drawn: append([]bool{}, g.Loyalty.Drawn...)}
+	g.mtx.Unlock()
+
+	g.log.Debug("runLoyalty(%d): %t", ri, card)
+
+	g.Broadcast(conn.MessageSend{
+		Group: "game",
+		Name:  "loyalty",
+		Body:  loyalty,
+	})
+
+	// the lancelots need to be masked again
+	if card {
+		g.Send()
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/toms1441/resistance-server/internal/conn"
 	"github.com/toms1441/resistance-server/internal/logger"
@@ -20,9 +19,18 @@ var (
 
 // IsAccepted is a method that returns a boolean value that represents if the mission was accept or not.
 func (m Mission) IsAccepted() bool {
+	if len(m.Rejected) > 0 {
+		return false
+	}
+
 	return len(m.Accept) > len(m.Decline)
 }
 
+// IsDeclined is a method that returns a boolean value that represents if the mission was proposed and then declined.
+func (m Mission) IsDeclined() bool {
+	return !m.IsEmpty() && !m.IsAccepted()
+}
+
 // IsEmtpy is a method that returns a boolean value that represents if the
 func (m Mission) IsEmpty() bool {
 	return len(m.Accept) == 0 && len(m.Decline) == 0 && len(m.Assignees) == 0
@@ -37,22 +45,62 @@ func (g *Game) runMission(ri, mi int) (b bool) {
 		g.SetCaptain()
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*1)
+	// the captain gets dealt plot cards at the start of every round
+	if g.Plot != nil && mi == 0 {
+		g.runPlotDeal(ri, mi)
+	}
+
+	// PlotCardStrongLeader changes the captain once the phase starts
+	id := g.captain
+
+	ctx, cancel, deadline := newPhase(g.timers.Choose)
 	g.startChoosingPhase(cancel, ri, mi)
+	if g.Plot != nil {
+		g.startStrongLeaderPhase(cancel, ri, mi, deadline)
+	}
+
+	g.record(Event{
+		Type:    EventCaptain,
+		Round:   ri,
+		Mission: mi,
+		Player:  id,
+	})
 
-	captain := g.Players[g.captain]
+	captain := g.Players[id]
 	g.log.Debug("captain = @%s#%s", captain.GetClient().Username, captain.GetClient().Discriminator)
 
 	// inform the players that we're in the choosing phase
 	// and give em the captain's ID
 	g.Broadcast(conn.MessageSend{
-		Group: "game",
-		Name:  "choose",
-		Body:  g.captain,
+		Group:    "game",
+		Name:     "choose",
+		Body:     id,
+		Deadline: deadline,
 	})
 
 	<-ctx.Done()
-	captain.RemoveCommandsByNames("game", "choose")
+	// the captain might've changed through PlotCardStrongLeader
+	g.mtx.Lock()
+	id = g.captain
+	g.mtx.Unlock()
+
+	g.Players[id].RemoveCommandsByNames("game", "choose")
+	if g.Plot != nil {
+		g.removePlotCommands()
+	}
+
+	// the captain didn't choose in time, so a random team gets picked
+	g.timeoutChoose(ri, mi)
+
+	g.mtx.Lock()
+	g.record(Event{
+		Type:    EventTeam,
+		Round:   ri,
+		Mission: mi,
+		Player:  g.captain,
+		Players: append([]string{}, g.Rounds[ri].Missions[mi].Assignees...),
+	})
+	g.mtx.Unlock()
 
 	// names of the assignees
 	assignees := []string{}
@@ -67,15 +115,16 @@ func (g *Game) runMission(ri, mi int) (b bool) {
 		assignees = append(assignees, fmt.Sprintf("@%s#%s", p.GetClient().Username, p.GetClient().Discriminator))
 	}
 
-	ctx, cancel = context.WithTimeout(context.Background(), time.Minute*3)
+	ctx, cancel, deadline = newPhase(g.timers.Vote)
 	g.startVotingPhase(cancel, ri, mi)
 
 	g.log.Debug("assignees = %v", assignees)
 
 	g.Broadcast(conn.MessageSend{
-		Group: "game",
-		Name:  "vote",
-		Body:  g.Rounds[ri].Missions[mi].Assignees,
+		Group:    "game",
+		Name:     "vote",
+		Body:     g.Rounds[ri].Missions[mi].Assignees,
+		Deadline: deadline,
 	})
 
 	<-ctx.Done()
@@ -85,6 +134,17 @@ func (g *Game) runMission(ri, mi int) (b bool) {
 		}
 	}
 
+	// players that didn't vote in time get their vote counted by the vote policy
+	g.timeoutVote(ri, mi)
+
+	// PlotCardNoConfidence and PlotCardKeepingCloseEye are played after the mission gets accepted
+	if g.Plot != nil && g.Rounds[ri].Missions[mi].IsAccepted() {
+		g.runPlotApproval(ri, mi)
+	}
+
+	// every vote is public, so is the outcome
+	g.sendVoteResult(ri, mi)
+
 	msh := g.Rounds[ri].Missions[mi]
 
 	g.log.Debug("missions[%d].IsAccepted: %t", mi, msh.IsAccepted())
@@ -101,6 +161,37 @@ func (g *Game) runMission(ri, mi int) (b bool) {
 	return
 }
 
+// sendVoteResult broadcasts game.vote_result, with every player's vote on the team.
+func (g *Game) sendVoteResult(ri, mi int) {
+	g.mtx.Lock()
+	mission := g.Rounds[ri].Missions[mi]
+
+	result := VoteResult{
+		Round:     ri,
+		Proposal:  mi,
+		Assignees: mission.Assignees,
+		Votes:     map[string]bool{},
+		Accepted:  mission.IsAccepted(),
+		Rejected:  mission.Rejected,
+		Remaining: g.Rounds[ri].Remaining(),
+	}
+
+	for _, id := range mission.Accept {
+		result.Votes[id] = true
+	}
+
+	for _, id := range mission.Decline {
+		result.Votes[id] = false
+	}
+	g.mtx.Unlock()
+
+	g.Broadcast(conn.MessageSend{
+		Group: "game",
+		Name:  "vote_result",
+		Body:  result,
+	})
+}
+
 // startChoosingPhase is a method for adding the game_choose method.
 // This function is called whenever the captain is set. which is whenever there is a mission avaliable.
 func (g *Game) startChoosingPhase(cancel context.CancelFunc, ri, mi int) {
@@ -142,6 +233,12 @@ func (g *Game) startChoosingPhase(cancel context.CancelFunc, ri, mi int) {
 				}
 
 				g.mtx.Lock()
+				// PlotCardStrongLeader might've changed the captain
+				if g.captain != captain.GetClient().ID {
+					g.mtx.Unlock()
+					return fmt.Errorf("player id: %s is no longer the captain", captain.GetClient().ID)
+				}
+
 				g.Rounds[ri].Missions[mi].Assignees = ids
 				g.mtx.Unlock()
 
@@ -179,6 +276,14 @@ func (g *Game) startVotingPhase(cancel context.CancelFunc, ri, mi int) {
 					mission := g.Rounds[ri].Missions[mi]
 					g.mtx.Unlock()
 
+					g.record(Event{
+						Type:    EventVote,
+						Round:   ri,
+						Mission: mi,
+						Player:  v.GetClient().ID,
+						Accept:  accept,
+					})
+
 					want := len(mission.Accept) + len(mission.Decline)
 					have := len(g.Players)
 
@@ -2,6 +2,7 @@ package game
 
 import (
 	"errors"
+	"math/rand/v2"
 	"sync"
 
 	"github.com/toms1441/resistance-server/internal/logger"
@@ -10,31 +11,88 @@ import (
 // Game is the game struct that gets called by lobby.Lobby
 // Game.Status gets set when 3 Rounds have been successful or a failure
 type Game struct {
+	// ID is a random id, it's used to find the game's replay
+	ID      string            `json:"id"`
 	Type    Type              `json:"type"`
 	Rounds  [5]Round          `json:"rounds"`
 	Option  Option            `json:"option"`
 	Status  Status            `json:"status"`
 	Players map[string]Player `json:"players"`
-
+	// Rules is the rule table for the amount of players.
+	Rules Rules `json:"rules"`
+	// Seats is the order in which the players sit, the captain moves along it.
 	// so we have more consistent captains
-	// it's basically Players but sorted alphabetically
-	playerids []string
+	// by id
+	Seats []string `json:"seats"`
+	// Assassination is only set in TypeAvalon, after the resistance wins 3 rounds.
+	Assassination *Assassination `json:"assassination,omitempty"`
+	// Lady is only set with OptionLady.
+	Lady *Lady `json:"lady,omitempty"`
+	// Loyalty is only set with OptionLancelot.
+	Loyalty *Loyalty `json:"loyalty,omitempty"`
+	// Plot is only set in TypeOriginal.
+	Plot *Plot `json:"plot,omitempty"`
+	// Investigations are only set in TypeHunter.
+	Investigations []Investigation `json:"investigations,omitempty"`
+	// Hunts are only set in TypeHunter, after either team wins 3 rounds. One for the hunter of each team.
+	Hunts []Hunt `json:"hunts,omitempty"`
+	// Seed is the seed of every random choice in the game, see WithSeed. It's never sent to the players.
+	// It's nil unless it was set, games without a seed can't be reproduced.
+	Seed *Seed `json:"seed,omitempty"`
 
 	captain string
-	log     logger.Logger
+	rand    *rand.Rand
+	// seatRand shuffles the seats, apart from rand so the seats don't give away the roles
+	seatRand *rand.Rand
+	// order is set by WithSeats
+	order    []string
+	log      logger.Logger
+	timers   Timers
+	policies Policies
+
+	// every state change in the game, see Game.Log
+	events []Event
+	emtx   sync.Mutex
+
+	// clients that watch the game without a seat, see AddSpectator and AddCaster
+	// by id
+	spectators map[string]*spectator
+	smtx       sync.Mutex
 
 	mtx sync.Mutex
 }
 
+// view is the game as it gets sent in game.get, see Game.mask. It's a separate struct so the mutexes of the game never get copied.
+// The seed isn't a part of it, since the seed gives away the roles.
+type view struct {
+	ID             string            `json:"id"`
+	Type           Type              `json:"type"`
+	Rounds         [5]Round          `json:"rounds"`
+	Option         Option            `json:"option"`
+	Status         Status            `json:"status"`
+	Players        map[string]Player `json:"players"`
+	Rules          Rules             `json:"rules"`
+	Seats          []string          `json:"seats"`
+	Assassination  *Assassination    `json:"assassination,omitempty"`
+	Lady           *Lady             `json:"lady,omitempty"`
+	Loyalty        *Loyalty          `json:"loyalty,omitempty"`
+	Plot           *Plot             `json:"plot,omitempty"`
+	Investigations []Investigation   `json:"investigations,omitempty"`
+	Hunts          []Hunt            `json:"hunts,omitempty"`
+}
+
 const (
 	// TypeBasic has spies and resistance.
 	TypeBasic Type = iota
-	// TypeOriginal no documentation yet.
+	// TypeOriginal same as TypeBasic only with plot cards.
+	// The captain gets dealt plot cards at the start of every round, then gives them to other players.
 	TypeOriginal
 	// TypeAvalon same as TypeBasic only with merlin.
 	// Merlin is a character that sees all spies and resistance.
 	TypeAvalon
-	// TypeHunter no documentation
+	// TypeHunter same as TypeBasic only with chiefs and hunters.
+	// After the 2nd, 3rd and 4th round the captain investigates if a player is a chief.
+	// Whenever a team wins 3 rounds the other team's hunter gets one last chance to win, by finding their chief.
 	TypeHunter
 	// TypeTrumpmode no documentation
 	TypeTrumpmode
@@ -75,6 +133,8 @@ type Round struct {
 	MinFailure uint8 `json:"minfailure"`
 	// the amount of players that wanted to fail the mission
 	Failure uint8 `json:"failure"`
+	// the amount of missions that can be proposed, if all of them are declined the spies win
+	Proposals uint8 `json:"proposals"`
 	// players that voted failure
 	// by id
 	failure []string
@@ -83,7 +143,7 @@ type Round struct {
 	success []string
 }
 
-// Mission is a struct that's used in the Rounds. For every round there are 5 Missions maximum, if all missions(5) are a failure the game is lost.
+// Mission is a struct that's used in the Rounds. For every round there are Round.Proposals Missions maximum, if all of them are a failure the game is lost.
 // Mission gets set whenever a captain picks X amount of players. Once they are picked, an event gets sent to every player whether they wanna accept the mission(i.e proceed with the mission) or decline the mission.
 type Mission struct {
 	// players that accepted the mission
@@ -93,6 +153,148 @@ type Mission struct {
 	// players that were in the mission
 	Assignees []string `json:"assignees"`
 	// all of the above is a slice of player ids
+
+	// the player that played PlotCardNoConfidence, which rejects the mission even if it was accepted
+	// by id
+	Rejected string `json:"rejected,omitempty"`
+}
+
+// VoteResult is the body of game.vote_result, it's broadcast once every player voted on a team.
+type VoteResult struct {
+	Round int `json:"round"`
+	// the position of the team in the round, starting from zero
+	Proposal int `json:"proposal"`
+	// players that were in the team
+	// by id
+	Assignees []string `json:"assignees"`
+	// the vote of each player, true means accept
+	// by id
+	Votes map[string]bool `json:"votes"`
+	// Accepted is false whenever the team got declined, or got rejected through PlotCardNoConfidence
+	Accepted bool `json:"accepted"`
+	// the player that played PlotCardNoConfidence
+	// by id
+	Rejected string `json:"rejected,omitempty"`
+	// the amount of teams that can still be declined before the spies win the round, see Round.Remaining
+	Remaining int `json:"remaining"`
+}
+
+// Assassination is the last phase of an Avalon game. It starts whenever the resistance wins 3 rounds.
+// The spies get to guess who Merlin is, if they guess right the spies win the game.
+type Assassination struct {
+	// players that are able to assassinate
+	// by id
+	Assassins []string `json:"assassins"`
+	// players that could be assassinated
+	// by id
+	Targets []string `json:"targets"`
+	// the player that got assassinated, empty if no one got assassinated
+	// by id
+	Target string `json:"target"`
+}
+
+// Lady is the lady of the lake token. After the 2nd, 3rd and 4th round the holder checks the loyalty of a player, then the token passes to that player.
+type Lady struct {
+	// the player that currently holds the token
+	// by id
+	Holder string `json:"holder"`
+	// players that could be checked by the holder, a player that held the token can't be checked
+	// by id
+	Targets []string `json:"targets"`
+	// every check that has been made, in order
+	History []LadyCheck `json:"history"`
+}
+
+// LadyCheck is a single use of the lady of the lake token. The result of the check is only sent to the holder.
+type LadyCheck struct {
+	// the round that the check was made after
+	Round int `json:"round"`
+	// the player that held the token
+	// by id
+	Holder string `json:"holder"`
+	// the player that got checked
+	// by id
+	Target string `json:"target"`
+}
+
+// Loyalty is the loyalty deck used with the lancelots. At the start of the 3rd, 4th and 5th round a card gets drawn, if it's a switch card the lancelots switch allegiance.
+type Loyalty struct {
+	// cards that have been drawn, in order. true means switch
+	Drawn []bool `json:"drawn"`
+	// cards that have not been drawn yet
+	deck []bool
+}
+
+// Plot is the plot card state in TypeOriginal. Just like the board game, the cards that players hold are public.
+type Plot struct {
+	// the captain that the cards were dealt to
+	// by id
+	Captain string `json:"captain"`
+	// cards that have been dealt to the captain, and have yet to be given to other players
+	Dealt []PlotCard `json:"dealt"`
+	// cards that each player holds
+	// by id
+	Hands map[string][]PlotCard `json:"hands"`
+	// every card that has been played, in order
+	History []PlotPlay `json:"history"`
+	// cards that have not been dealt yet
+	deck []PlotCard
+	// players that don't want to play any card in the current phase
+	// by id
+	passed map[string]bool
+}
+
+// PlotPlay is a single use of a plot card. It's also the body of game.give and game.plot
+type PlotPlay struct {
+	// the round and mission in which the card was played
+	Round   int `json:"round"`
+	Mission int `json:"mission"`
+	// the card that was played, PlotCardNone means pass
+	Card PlotCard `json:"card"`
+	// the player that played the card
+	// by id
+	Player string `json:"player"`
+	// the player that the card was played on, it's empty for cards that don't need a target
+	// by id
+	Target string `json:"target"`
+}
+
+// Investigation is a single use of the investigation action in TypeHunter. The result of the investigation is only sent to the investigator.
+type Investigation struct {
+	// the round that the investigation was made after
+	Round int `json:"round"`
+	// the captain that investigates
+	// by id
+	Investigator string `json:"investigator"`
+	// players that could be investigated
+	// by id
+	Targets []string `json:"targets"`
+	// the player that got investigated, empty if no one got investigated
+	// by id
+	Target string `json:"target"`
+}
+
+// InvestigationResult is the body of game.investigation, which only gets sent to the investigator.
+type InvestigationResult struct {
+	// the player that got investigated
+	// by id
+	Target string `json:"target"`
+	// true if the player is a chief of either team
+	Chief bool `json:"chief"`
+}
+
+// Hunt is the last phase of a Hunter game. It starts whenever a team wins 3 rounds, and the hunter of each team gets to guess who the chief of the other team is.
+// If the hunter of the losing team guesses right their team wins the game, unless the hunter of the winning team guessed right as well.
+type Hunt struct {
+	// the player that hunts
+	// by id
+	Hunter string `json:"hunter"`
+	// players that could be accused
+	// by id
+	Targets []string `json:"targets"`
+	// the player that got accused, empty if no one got accused
+	// by id
+	Target string `json:"target"`
 }
 
 const (
@@ -122,7 +324,56 @@ func (s Status) String() string {
 	return val
 }
 
+const (
+	// ReasonDefault is the reason of a game that isn't finished
+	ReasonDefault Reason = iota
+	// ReasonMissions means a team won 3 rounds
+	ReasonMissions
+	// ReasonProposals means every team that was proposed in a round got rejected, so the spies won
+	ReasonProposals
+	// ReasonAssassination means the spies assassinated merlin
+	ReasonAssassination
+	// ReasonHunt means the hunter of the losing team found the chief of the winning team, and the chief of the losing team wasn't found
+	ReasonHunt
+)
+
+// Reason is the reason that the game ended with its status.
+type Reason uint8
+
+var reasonStrings = map[Reason]string{
+	ReasonDefault:       "Default",
+	ReasonMissions:      "Missions",
+	ReasonProposals:     "Proposals",
+	ReasonAssassination: "Assassination",
+	ReasonHunt:          "Hunt",
+}
+
+func (r Reason) String() string {
+	val, ok := reasonStrings[r]
+	if !ok {
+		return ""
+	}
+
+	return val
+}
+
+// End is the body of game.end, it's broadcast once the game is finished. Nothing is masked anymore.
+type End struct {
+	Status Status `json:"status"`
+	Reason Reason `json:"reason"`
+	// players that won the game
+	// by id
+	Winners []string `json:"winners"`
+	// the final role of every player
+	// by id
+	Players map[string]PlayerType `json:"players"`
+}
+
 var (
 	// ErrInvalidClients occurs when len(clients) < 5 || len(clients) > 10
 	ErrInvalidClients = errors.New("game contains less than 5 players or more than 10 players")
+	// ErrSeats occurs when the seats that were set by WithSeats aren't the ids of every player
+	ErrSeats = errors.New("game seats must contain every player once")
+	// ErrSpyRoles occurs when the options contain more spy roles(assassin, mordred, oberon, lancelot, chief, hunter) than the amount of spies in the game
+	ErrSpyRoles = errors.New("game options contain more spy roles than the amount of spies")
 )
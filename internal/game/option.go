@@ -1,5 +1,11 @@
 package game
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
 const (
 	// OptionNone means no options
 	OptionNone Option = 0
@@ -7,8 +13,24 @@ const (
 	OptionPercival Option = 1 << iota
 	// OptionMorgana includes PlayerTypePercival,PlayerTypeMorgana in the game
 	OptionMorgana
+	// OptionAssassin includes PlayerTypeAssassin in the game, only the assassin gets to assassinate merlin.
+	OptionAssassin
+	// OptionLady includes the lady of the lake in the game, the token holder gets to check a player's loyalty after the 2nd, 3rd and 4th round.
+	OptionLady
+	// OptionOberon includes PlayerTypeOberon in the game
+	OptionOberon
+	// OptionMordred includes PlayerTypeMordred in the game
+	OptionMordred
+	// OptionLancelot includes PlayerTypeLancelotGood,PlayerTypeLancelotEvil and the loyalty deck in the game
+	OptionLancelot
 )
 
+// avalonOptions are the options that only exist in TypeAvalon
+const avalonOptions = OptionPercival | OptionMorgana | OptionAssassin | OptionOberon | OptionMordred | OptionLancelot
+
+// ErrAvalonOption occurs when an option that only exists in TypeAvalon is used with another type
+var ErrAvalonOption = errors.New("option must be equipped with type avalon")
+
 // Option represents the Game options, whether to include certain PlayerTypes or not.
 // it's stored in the form of bitmasks
 type Option uint8
@@ -32,3 +54,47 @@ func (o Option) Toggle(option Option) Option {
 func (o Option) Has(option Option) bool {
 	return o&option != 0
 }
+
+// Validate returns ErrAvalonOption if (o Option) contains an option that doesn't exist in the type
+func (o Option) Validate(t uint8) error {
+	if t == TypeAvalon.Common() {
+		return nil
+	}
+
+	for _, v := range optionStrings {
+		if avalonOptions.Has(v.option) && o.Has(v.option) {
+			return fmt.Errorf("%w: %s", ErrAvalonOption, strings.ToLower(v.str))
+		}
+	}
+
+	return nil
+}
+
+var optionStrings = []struct {
+	option Option
+	str    string
+}{
+	{OptionPercival, "Percival"},
+	{OptionMorgana, "Morgana"},
+	{OptionAssassin, "Assassin"},
+	{OptionLady, "Lady"},
+	{OptionOberon, "Oberon"},
+	{OptionMordred, "Mordred"},
+	{OptionLancelot, "Lancelot"},
+}
+
+// String returns the options joined by a plus sign, i.e "Percival+Morgana". OptionNone returns "None".
+func (o Option) String() string {
+	strs := []string{}
+	for _, v := range optionStrings {
+		if o.Has(v.option) {
+			strs = append(strs, v.str)
+		}
+	}
+
+	if len(strs) == 0 {
+		return "None"
+	}
+
+	return strings.Join(strs, "+")
+}
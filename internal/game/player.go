@@ -33,11 +33,35 @@ const (
 	// PlayerTypeMorgana is a part of PlayerTypeSpy, which means Morgana knows who are the spies and who are resistance.
 	// Morgana appears as a Merlin to Percival.
 	PlayerTypeMorgana
+	// PlayerTypeAssassin is a part of PlayerTypeSpy.
+	// The Assassin is the only spy that gets to guess who Merlin is at the end of the game.
+	PlayerTypeAssassin
+	// PlayerTypeOberon is a part of PlayerTypeSpy.
+	// Oberon doesn't know who the spies are, and the spies don't know who Oberon is.
+	PlayerTypeOberon
+	// PlayerTypeMordred is a part of PlayerTypeSpy.
+	// Mordred is hidden from Merlin.
+	PlayerTypeMordred
+	// PlayerTypeLancelotGood is a part of PlayerTypeResistance.
+	// The lancelots switch allegiance whenever a switch card gets drawn from the loyalty deck.
+	PlayerTypeLancelotGood
+	// PlayerTypeLancelotEvil is a part of PlayerTypeSpy.
+	// The lancelots switch allegiance whenever a switch card gets drawn from the loyalty deck.
+	PlayerTypeLancelotEvil
+	// PlayerTypeChiefResistance is a part of PlayerTypeResistance, only in TypeHunter.
+	// The spy hunter tries to find the resistance chief at the end of the game.
+	PlayerTypeChiefResistance
+	// PlayerTypeHunterResistance is a part of PlayerTypeResistance, only in TypeHunter.
+	// The resistance hunter gets to accuse a player of being the spy chief whenever the spies win 3 rounds.
+	PlayerTypeHunterResistance
+	// PlayerTypeChiefSpy is a part of PlayerTypeSpy, only in TypeHunter.
+	// The resistance hunter tries to find the spy chief at the end of the game.
+	PlayerTypeChiefSpy
+	// PlayerTypeHunterSpy is a part of PlayerTypeSpy, only in TypeHunter.
+	// The spy hunter gets to accuse a player of being the resistance chief whenever the resistance wins 3 rounds.
+	PlayerTypeHunterSpy
 	/*
 	*	these will be added later
-	*	PLAYER_TYPE_OBERON
-	*	PLAYER_TYPE_MORDRED
-	*	PLAYER_TYPE_LANCELOT
 	*	PLAYER_TYPE_LADY // lady of the lake
 	*	PLAYER_TYPE_EXCALIBUR
 	*	PLAYER_TYPE_NOREBO
@@ -47,12 +71,54 @@ const (
 )
 
 // PlayerType is a uint8 representation of the player type.
-// Values are between PlayerTypeDefault and PlayerTypeMorgana
+// Values are between PlayerTypeDefault and PlayerTypeHunterSpy
 type PlayerType uint8
 
+var playerTypeStrings = map[PlayerType]string{
+	PlayerTypeResistance:       "Resistance",
+	PlayerTypeSpy:              "Spy",
+	PlayerTypeMerlin:           "Merlin",
+	PlayerTypePercival:         "Percival",
+	PlayerTypeMorgana:          "Morgana",
+	PlayerTypeAssassin:         "Assassin",
+	PlayerTypeOberon:           "Oberon",
+	PlayerTypeMordred:          "Mordred",
+	PlayerTypeLancelotGood:     "Lancelot Good",
+	PlayerTypeLancelotEvil:     "Lancelot Evil",
+	PlayerTypeChiefResistance:  "Chief Resistance",
+	PlayerTypeHunterResistance: "Hunter Resistance",
+	PlayerTypeChiefSpy:         "Chief Spy",
+	PlayerTypeHunterSpy:        "Hunter Spy",
+}
+
+func (pt PlayerType) String() string {
+	val, ok := playerTypeStrings[pt]
+	if !ok {
+		return ""
+	}
+
+	return val
+}
+
+// IsSpy returns a boolean value representing if the PlayerType is a part of PlayerTypeSpy.
+func (pt PlayerType) IsSpy() bool {
+	switch pt {
+	case PlayerTypeSpy, PlayerTypeMorgana, PlayerTypeAssassin, PlayerTypeOberon, PlayerTypeMordred, PlayerTypeLancelotEvil, PlayerTypeChiefSpy, PlayerTypeHunterSpy:
+		return true
+	}
+
+	return false
+}
+
+// IsChief returns a boolean value representing if the PlayerType is a chief of either team.
+func (pt PlayerType) IsChief() bool {
+	return pt == PlayerTypeChiefResistance || pt == PlayerTypeChiefSpy
+}
+
 func newPlayer(c conn.Conn) Player {
 	return Player{
-		Conn: c,
+		// the seat lets the player reconnect to the game, see Game.Rebind
+		Conn: newSeat(c),
 		Type: PlayerTypeDefault,
 	}
 }
@@ -0,0 +1,543 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+// PlotCard is a card that's only used in TypeOriginal.
+// Immediate cards have to be played as soon as they are given, the rest are kept until they are played.
+type PlotCard uint8
+
+const (
+	// PlotCardNone is used to pass, i.e to not play any card.
+	PlotCardNone PlotCard = iota
+	// PlotCardStrongLeader is kept, it's played before the captain chooses the assignees.
+	// The player that played it becomes the captain.
+	PlotCardStrongLeader
+	// PlotCardNoConfidence is kept, it's played after a mission gets accepted.
+	// The mission gets rejected.
+	PlotCardNoConfidence
+	// PlotCardKeepingCloseEye is kept, it's played after a mission gets accepted.
+	// The player gets to see the card that one of the assignees decided on.
+	PlotCardKeepingCloseEye
+	// PlotCardOverheardConversation is immediate.
+	// The player gets to see the loyalty of a player that sits next to them.
+	PlotCardOverheardConversation
+	// PlotCardOpenUp is immediate.
+	// The player reveals their loyalty to a player of their choice.
+	PlotCardOpenUp
+)
+
+var plotCardStrings = map[PlotCard]string{
+	PlotCardNone:                  "None",
+	PlotCardStrongLeader:          "Strong Leader",
+	PlotCardNoConfidence:          "No Confidence",
+	PlotCardKeepingCloseEye:       "Keeping a Close Eye on You",
+	PlotCardOverheardConversation: "Overheard Conversation",
+	PlotCardOpenUp:                "Open Up",
+}
+
+func (pc PlotCard) String() string {
+	val, ok := plotCardStrings[pc]
+	if !ok {
+		return ""
+	}
+
+	return val
+}
+
+// MarshalJSON is needed, otherwise []PlotCard gets encoded as a base64 string.
+func (pc PlotCard) MarshalJSON() ([]byte, error) {
+	return json.Marshal(uint8(pc))
+}
+
+// IsImmediate returns a boolean value representing if the card has to be played as soon as it's given.
+func (pc PlotCard) IsImmediate() bool {
+	return pc == PlotCardOverheardConversation || pc == PlotCardOpenUp
+}
+
+// PlotDeck is the deck of plot cards that gets shuffled at the start of every TypeOriginal game.
+var PlotDeck = []PlotCard{
+	PlotCardStrongLeader, PlotCardStrongLeader,
+	PlotCardNoConfidence, PlotCardNoConfidence, PlotCardNoConfidence,
+	PlotCardKeepingCloseEye, PlotCardKeepingCloseEye,
+	PlotCardOverheardConversation, PlotCardOverheardConversation,
+	PlotCardOpenUp, PlotCardOpenUp,
+}
+
+// PlotDeal is a map containing the amount of plot cards that get dealt to the captain at the start of every round, represented by the amount of players.
+var PlotDeal = map[int]int{
+	5:  1,
+	6:  1,
+	7:  2,
+	8:  2,
+	9:  3,
+	10: 3,
+}
+
+// PlotEye is the result of PlotCardKeepingCloseEye, it's only sent to the player that played the card.
+type PlotEye struct {
+	// the assignee that was watched
+	// by id
+	Target string `json:"target"`
+	// the card that the assignee decided on
+	Success bool `json:"success"`
+}
+
+var (
+	// ErrPlotCard is returned whenever a player doesn't have the card, or the card can't be played in the current phase
+	ErrPlotCard = errors.New("Plot card cannot be played")
+)
+
+// has returns a boolean value representing if the player holds the card.
+func (pl *Plot) has(id string, card PlotCard) bool {
+	for _, v := range pl.Hands[id] {
+		if v == card {
+			return true
+		}
+	}
+
+	return false
+}
+
+// take removes the card from the player's hand.
+func (pl *Plot) take(id string, card PlotCard) {
+	hand := pl.Hands[id]
+	for k, v := range hand {
+		if v == card {
+			pl.Hands[id] = append(hand[:k], hand[k+1:]...)
+			return
+		}
+	}
+}
+
+// holders returns the players that hold one of the cards, and haven't passed.
+func (pl *Plot) holders(cards ...PlotCard) []string {
+	ids := []string{}
+	for id := range pl.Hands {
+		if pl.passed[id] {
+			continue
+		}
+
+		for _, card := range cards {
+			if pl.has(id, card) {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+
+	return ids
+}
+
+// copy returns a copy of the plot that can be sent while the cards change, nil stays nil. g.mtx needs to be locked before calling it.
+func (pl *Plot) copy() *Plot {
+	if pl == nil {
+		return nil
+	}
+
+	c := &Plot{
+		Captain: pl.Captain,
+		Dealt:   append([]PlotCard{}, pl.Dealt...),
+		Hands:   map[string][]PlotCard{},
+		History: append([]PlotPlay{}, pl.History...),
+	}
+
+	for id, hand := range pl.Hands {
+		c.Hands[id] = append([]PlotCard{}, hand...)
+	}
+
+	return c
+}
+
+// newPlot shuffles the plot deck.
+func (g *Game) newPlot() {
+	deck := make([]PlotCard, len(PlotDeck))
+	copy(deck, PlotDeck)
+
+	g.rand.Shuffle(len(deck), func(i, j int) {
+		deck[i], deck[j] = deck[j], deck[i]
+	})
+
+	g.Plot = &Plot{
+		Dealt:   []PlotCard{},
+		Hands:   map[string][]PlotCard{},
+		History: []PlotPlay{},
+		deck:    deck,
+		passed:  map[string]bool{},
+	}
+}
+
+// runPlotDeal deals plot cards to the captain, it's a blocking method that returns whenever every card has been given and every immediate card has been played.
+func (g *Game) runPlotDeal(ri, mi int) {
+	g.mtx.Lock()
+	amount := PlotDeal[len(g.Players)]
+	if amount > len(g.Plot.deck) {
+		amount = len(g.Plot.deck)
+	}
+
+	if amount == 0 {
+		g.mtx.Unlock()
+		return
+	}
+
+	g.Plot.Captain = g.captain
+	g.Plot.Dealt = append([]PlotCard{}, g.Plot.deck[:amount]...)
+	g.Plot.deck = g.Plot.deck[amount:]
+	g.Plot.passed = map[string]bool{}
+	g.mtx.Unlock()
+
+	g.log.Debug("runPlotDeal(%d, %d): %v", ri, mi, g.Plot.Dealt)
+
+	immediate := []PlotCard{PlotCardOverheardConversation, PlotCardOpenUp}
+	done := func() bool {
+		return len(g.Plot.Dealt) == 0 && len(g.Plot.holders(immediate...)) == 0
+	}
+
+	ctx, cancel, deadline := newPhase(g.timers.Deal)
+	g.startGivingPhase(cancel, ri, mi, done)
+	g.startPlotPhase(cancel, ri, mi, immediate, done, nil)
+
+	// the captain might already be giving cards away
+	g.mtx.Lock()
+	plot := g.Plot.copy()
+	g.mtx.Unlock()
+
+	// inform the players about the dealt cards
+	g.Broadcast(conn.MessageSend{
+		Group:    "game",
+		Name:     "deal",
+		Body:     plot,
+		Deadline: deadline,
+	})
+
+	<-ctx.Done()
+	g.Players[g.Plot.Captain].RemoveCommandsByNames("game", "give")
+	g.removePlotCommands()
+
+	// cards that weren't given or played in time are discarded
+	g.mtx.Lock()
+	g.Plot.Dealt = []PlotCard{}
+	for id := range g.Plot.Hands {
+		for _, card := range immediate {
+			for g.Plot.has(id, card) {
+				g.Plot.take(id, card)
+			}
+		}
+	}
+	g.mtx.Unlock()
+
+	// the immediate cards might've revealed a player's loyalty
+	g.Send()
+}
+
+// startGivingPhase is a method for adding the game.give command to the captain.
+func (g *Game) startGivingPhase(cancel context.CancelFunc, ri, mi int, done func() bool) {
+	captain, ok := g.Players[g.Plot.Captain]
+	if !ok {
+		return
+	}
+
+	captain.AddCommand("game", conn.MessageStruct{
+		"give": func(log logger.Logger, body []byte) error {
+			play := PlotPlay{}
+
+			err := json.Unmarshal(body, &play)
+			if err != nil {
+				return fmt.Errorf("json.Unmarshal: %v", err)
+			}
+
+			g.mtx.Lock()
+			index := -1
+			for k, v := range g.Plot.Dealt {
+				if v == play.Card {
+					index = k
+					break
+				}
+			}
+
+			if index == -1 {
+				g.mtx.Unlock()
+				return ErrPlotCard
+			}
+
+			// the captain can't give cards to themselves
+			_, ok := g.Players[play.Target]
+			if !ok || play.Target == g.Plot.Captain {
+				g.mtx.Unlock()
+				return ErrInvalidPlayer
+			}
+
+			g.Plot.Dealt = append(g.Plot.Dealt[:index], g.Plot.Dealt[index+1:]...)
+			g.Plot.Hands[play.Target] = append(g.Plot.Hands[play.Target], play.Card)
+			g.record(Event{
+				Type:    EventPlot,
+				Round:   ri,
+				Mission: mi,
+				Player:  g.Plot.Captain,
+				Target:  play.Target,
+				Card:    play.Card,
+			})
+
+			finished := done()
+			plot := g.Plot.copy()
+			g.mtx.Unlock()
+
+			g.Broadcast(conn.MessageSend{
+				Group: "game",
+				Name:  "deal",
+				Body:  plot,
+			})
+
+			if finished {
+				cancel()
+			}
+
+			return nil
+		},
+	})
+}
+
+// startPlotPhase is a method for adding the game.plot command to every player, only cards that are included in cards can be played.
+// done is called after every play, if it returns true the phase gets cancelled. played is called after a card has been played.
+func (g *Game) startPlotPhase(cancel context.CancelFunc, ri, mi int, cards []PlotCard, done func() bool, played func(play PlotPlay)) {
+	for _, v := range g.Players {
+		if !v.IsValid() {
+			continue
+		}
+
+		p := v
+		p.AddCommand("game", conn.MessageStruct{
+			"plot": func(log logger.Logger, body []byte) error {
+				play := PlotPlay{}
+
+				err := json.Unmarshal(body, &play)
+				if err != nil {
+					return fmt.Errorf("json.Unmarshal: %v", err)
+				}
+
+				play.Round = ri
+				play.Mission = mi
+				play.Player = p.GetClient().ID
+
+				g.mtx.Lock()
+				play, err = g.playPlot(play, cards)
+				finished := done != nil && done()
+				g.mtx.Unlock()
+
+				if err != nil {
+					return err
+				}
+
+				if play.Card != PlotCardNone {
+					g.Broadcast(conn.MessageSend{
+						Group: "game",
+						Name:  "plot",
+						Body:  play,
+					})
+
+					if played != nil {
+						played(play)
+					}
+				}
+
+				if finished {
+					cancel()
+				}
+
+				return nil
+			},
+		})
+	}
+}
+
+// playPlot validates the card and applies its effect. g.mtx needs to be locked before calling it.
+func (g *Game) playPlot(play PlotPlay, cards []PlotCard) (PlotPlay, error) {
+	if play.Card == PlotCardNone {
+		// immediate cards have to be played
+		for _, v := range cards {
+			if v.IsImmediate() {
+				return play, ErrPlotCard
+			}
+		}
+
+		g.Plot.passed[play.Player] = true
+		return play, nil
+	}
+
+	allowed := false
+	for _, v := range cards {
+		if v == play.Card {
+			allowed = true
+			break
+		}
+	}
+
+	if !allowed || !g.Plot.has(play.Player, play.Card) {
+		return play, fmt.Errorf("%w: %s", ErrPlotCard, play.Card.String())
+	}
+
+	mission := &g.Rounds[play.Round].Missions[play.Mission]
+	switch play.Card {
+	case PlotCardStrongLeader:
+		if play.Player == g.captain {
+			return play, fmt.Errorf("player id: %s is already the captain", play.Player)
+		}
+
+		if len(mission.Assignees) > 0 {
+			return play, fmt.Errorf("%w: the captain has already chosen", ErrPlotCard)
+		}
+
+		play.Target = ""
+		g.captain = play.Player
+	case PlotCardNoConfidence:
+		play.Target = ""
+		mission.Rejected = play.Player
+	case PlotCardKeepingCloseEye:
+		valid := false
+		for _, v := range mission.Assignees {
+			if v == play.Target && v != play.Player {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			return play, ErrInvalidPlayer
+		}
+	case PlotCardOverheardConversation:
+		index := -1
+		for k, v := range g.Seats {
+			if v == play.Player {
+				index = k
+				break
+			}
+		}
+
+		// only the players next to the player
+		length := len(g.Seats)
+		left, right := g.Seats[(index+length-1)%length], g.Seats[(index+1)%length]
+		if play.Target != left && play.Target != right {
+			return play, ErrInvalidPlayer
+		}
+	case PlotCardOpenUp:
+		_, ok := g.Players[play.Target]
+		if !ok || play.Target == play.Player {
+			return play, ErrInvalidPlayer
+		}
+	}
+
+	g.Plot.take(play.Player, play.Card)
+	g.Plot.History = append(g.Plot.History, play)
+	g.record(Event{
+		Type:    EventPlot,
+		Round:   play.Round,
+		Mission: play.Mission,
+		Player:  play.Player,
+		Target:  play.Target,
+		Card:    play.Card,
+	})
+
+	return play, nil
+}
+
+// removePlotCommands removes the game.plot command from every player.
+func (g *Game) removePlotCommands() {
+	for _, v := range g.Players {
+		if v.IsValid() {
+			v.RemoveCommandsByNames("game", "plot")
+		}
+	}
+}
+
+// startStrongLeaderPhase lets the players play PlotCardStrongLeader while the captain is choosing, the player that played it becomes the captain.
+func (g *Game) startStrongLeaderPhase(cancel context.CancelFunc, ri, mi int, deadline *time.Time) {
+	g.startPlotPhase(cancel, ri, mi, []PlotCard{PlotCardStrongLeader}, nil, func(play PlotPlay) {
+		for k, v := range g.Players {
+			if k != play.Player && v.IsValid() {
+				v.RemoveCommandsByNames("game", "choose")
+			}
+		}
+
+		g.startChoosingPhase(cancel, ri, mi)
+
+		// inform the players about the new captain, the choosing phase keeps its deadline
+		g.Broadcast(conn.MessageSend{
+			Group:    "game",
+			Name:     "choose",
+			Body:     play.Player,
+			Deadline: deadline,
+		})
+	})
+}
+
+// runPlotApproval is a blocking method that lets the players play PlotCardNoConfidence and PlotCardKeepingCloseEye after a mission has been accepted.
+// it returns whenever every player that holds one of those cards played or passed, or the time ran out.
+func (g *Game) runPlotApproval(ri, mi int) {
+	cards := []PlotCard{PlotCardNoConfidence, PlotCardKeepingCloseEye}
+
+	g.mtx.Lock()
+	g.Plot.passed = map[string]bool{}
+	holders := g.Plot.holders(cards...)
+	g.mtx.Unlock()
+
+	if len(holders) == 0 {
+		return
+	}
+
+	done := func() bool {
+		return len(g.Rounds[ri].Missions[mi].Rejected) > 0 || len(g.Plot.holders(cards...)) == 0
+	}
+
+	ctx, cancel, deadline := newPhase(g.timers.React)
+	g.startPlotPhase(cancel, ri, mi, cards, done, nil)
+
+	// inform the players that the holders can react to the accepted mission
+	g.Broadcast(conn.MessageSend{
+		Group:    "game",
+		Name:     "react",
+		Body:     holders,
+		Deadline: deadline,
+	})
+
+	<-ctx.Done()
+	g.removePlotCommands()
+}
+
+// sendPlotEyes sends the result of PlotCardKeepingCloseEye to the players that played it in the mission.
+func (g *Game) sendPlotEyes(ri, mi int) {
+	for _, play := range g.Plot.History {
+		if play.Round != ri || play.Mission != mi || play.Card != PlotCardKeepingCloseEye {
+			continue
+		}
+
+		p, ok := g.Players[play.Player]
+		if !ok || !p.IsValid() {
+			continue
+		}
+
+		eye := PlotEye{
+			Target:  play.Target,
+			Success: true,
+		}
+
+		for _, v := range g.Rounds[ri].failure {
+			if v == play.Target {
+				eye.Success = false
+			}
+		}
+
+		p.WriteMessage(conn.MessageSend{
+			Group: "game",
+			Name:  "eye",
+			Body:  eye,
+		})
+	}
+}
@@ -4,119 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/toms1441/resistance-server/internal/conn"
 	"github.com/toms1441/resistance-server/internal/logger"
 )
 
-// RoundDefaults is map containing 5 Rounds represented by int.
-// It's used to determine how many Assignees are required and how many players are needed to Represent a round failure.
-var RoundDefaults = map[int][5]Round{
-	5: {
-		{
-			Assignees:  2,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  3,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  2,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  3,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  3,
-			MinFailure: 1,
-		},
-	},
-	// 5: 2, 3, 2, 3, 3
-
-	6: {
-		{
-			Assignees:  2,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  3,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  4,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  3,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  4,
-			MinFailure: 1,
-		},
-	},
-	// 6: 2, 3, 4, 3, 4
-
-	7: {
-		{
-			Assignees:  2,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  3,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  3,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  4,
-			MinFailure: 2,
-		},
-		{
-			Assignees:  4,
-			MinFailure: 1,
-		},
-	},
-	// 7: 2, 3, 3, 4*, 4
-
-	8: {
-		{
-			Assignees:  3,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  4,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  4,
-			MinFailure: 1,
-		},
-		{
-			Assignees:  5,
-			MinFailure: 2,
-		},
-		{
-			Assignees:  5,
-			MinFailure: 1,
-		},
-	},
-	// 8: 3, 4, 4, 5*, 5
-	// this is used for 9 players and 10 players aswell
-}
-
 // GetConculsion is a method that returns a boolean value if the resistance
 func (r Round) GetConculsion() Status {
 
 	var missionDeclined int
 	// loop over the missions in-order to determine if the spies won
-	// if all the missions(5) have been declined then the spies won
+	// if all the proposed missions have been declined then the spies won
 
 	if r.Missions[0].IsEmpty() {
 		return StatusDefault
@@ -132,7 +30,7 @@ func (r Round) GetConculsion() Status {
 		}
 	}
 
-	if missionDeclined == 5 {
+	if missionDeclined == int(r.Proposals) {
 		return StatusLost
 	}
 
@@ -144,10 +42,29 @@ func (r Round) GetConculsion() Status {
 
 }
 
-// b = if mi == 5
+// Remaining returns the amount of teams that can still be declined in the round, the spies win the round once it reaches zero.
+func (r Round) Remaining() int {
+	declined := 0
+	for _, v := range r.Missions {
+		if v.IsDeclined() {
+			declined++
+		}
+	}
+
+	return int(r.Proposals) - declined
+}
+
+// b = if mi == proposals
 func (g *Game) runRound(ri int) (b bool) {
+	// the loyalty deck is drawn from at the start of the 3rd, 4th and 5th round
+	if g.Loyalty != nil && ri >= 2 {
+		g.runLoyalty(ri)
+	}
+
+	proposals := int(g.Rounds[ri].Proposals)
+
 	var mi = 0
-	for ; mi < 5; mi++ {
+	for ; mi < proposals; mi++ {
 		// if a mission has been successful then break the loop
 		success := g.runMission(ri, mi)
 		g.log.Debug("end of g.runMission(%d, %d): %t", ri, mi, success)
@@ -157,8 +74,15 @@ func (g *Game) runRound(ri int) (b bool) {
 	}
 	g.log.Debug("g.runMission(%d)", ri)
 
-	if mi == 5 {
-		g.log.Debug("mi == 5")
+	if mi == proposals {
+		g.log.Debug("mi == %d", proposals)
+		g.record(Event{
+			Type:    EventRound,
+			Round:   ri,
+			Mission: proposals - 1,
+			Status:  StatusLost,
+		})
+
 		return true
 	}
 
@@ -176,25 +100,45 @@ func (g *Game) runRound(ri int) (b bool) {
 		assignees = append(assignees, fmt.Sprintf("@%s#%s", p.GetClient().Username, p.GetClient().Discriminator))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	ctx, cancel, deadline := newPhase(g.timers.Decide)
 
 	g.startDecidingPhase(cancel, ri, mi)
 
 	g.log.Debug("assignees = %v", assignees)
 
 	g.Broadcast(conn.MessageSend{
-		Group: "game",
-		Name:  "decide",
-		Body:  g.Rounds[ri].Missions[mi].Assignees,
+		Group:    "game",
+		Name:     "decide",
+		Body:     g.Rounds[ri].Missions[mi].Assignees,
+		Deadline: deadline,
 	})
 
 	<-ctx.Done()
+	for _, id := range g.Rounds[ri].Missions[mi].Assignees {
+		g.Players[id].RemoveCommandsByNames("game", "decide")
+	}
+
+	// assignees that didn't decide in time get their card counted by the decide policy
+	g.timeoutDecide(ri, mi)
+
 	// after it's done
 	// send the round result
 	g.mtx.Lock()
 	g.Rounds[ri].Failure = uint8(len(g.Rounds[ri].failure))
+	g.record(Event{
+		Type:    EventRound,
+		Round:   ri,
+		Mission: mi,
+		Status:  g.Rounds[ri].GetConculsion(),
+		Failure: g.Rounds[ri].Failure,
+	})
 	g.mtx.Unlock()
 
+	// players that played PlotCardKeepingCloseEye get to see the card of their target
+	if g.Plot != nil {
+		g.sendPlotEyes(ri, mi)
+	}
+
 	g.Broadcast(conn.MessageSend{
 		Group: "game",
 		Name:  "round",
@@ -246,7 +190,7 @@ func (g *Game) startDecidingPhase(cancel context.CancelFunc, ri int, mi int) err
 				success := true
 
 				// unless you are a spy >:)
-				if p.Type == PlayerTypeSpy || p.Type == PlayerTypeMorgana {
+				if p.Type.IsSpy() {
 					err := json.Unmarshal(bytes, &success)
 					if err != nil {
 						return fmt.Errorf("json.Unmarshal: %v", err)
@@ -262,6 +206,14 @@ func (g *Game) startDecidingPhase(cancel context.CancelFunc, ri int, mi int) err
 
 				round := g.Rounds[ri]
 				g.mtx.Unlock()
+
+				g.record(Event{
+					Type:    EventCard,
+					Round:   ri,
+					Mission: mi,
+					Player:  p.GetClient().ID,
+					Success: success,
+				})
 				want := int(round.Assignees)
 				have := len(round.success) + len(round.failure)
 
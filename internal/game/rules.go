@@ -0,0 +1,129 @@
+package game
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+//go:embed rules.json
+var rulesJSON []byte
+
+// DefaultRuleset is the official ruleset, it's embedded from rules.json.
+var DefaultRuleset = mustRuleset(rulesJSON)
+
+// Rules is the rule table for a single amount of players.
+type Rules struct {
+	// the exact amount of assignees in each round
+	Assignees [5]uint8 `json:"assignees"`
+	// the minimum amount of failure in each round, the double-fail mission has 2
+	MinFailure [5]uint8 `json:"minfailure"`
+	// the amount of spies
+	Spies int `json:"spies"`
+	// the amount of missions that can be proposed in a round, the spies win whenever all of them get declined
+	Proposals int `json:"proposals"`
+}
+
+// Ruleset is a map containing Rules represented by the amount of players.
+type Ruleset map[int]Rules
+
+var (
+	// ErrRulesetEmpty occurs when the ruleset doesn't contain any rules
+	ErrRulesetEmpty = errors.New("ruleset doesn't contain any rules")
+	// ErrRuleset occurs when the ruleset doesn't contain rules for the amount of players in the game
+	ErrRuleset = errors.New("ruleset doesn't contain rules for the amount of players")
+	// ErrRulesPlayers occurs when the amount of players is less than 5 or more than 10
+	ErrRulesPlayers = errors.New("rules must be for 5 to 10 players")
+	// ErrRulesAssignees occurs when the amount of assignees is zero or more than the amount of players
+	ErrRulesAssignees = errors.New("rules assignees must be between 1 and the amount of players")
+	// ErrRulesMinFailure occurs when the minimum amount of failure is zero or more than the amount of assignees
+	ErrRulesMinFailure = errors.New("rules minfailure must be between 1 and the amount of assignees")
+	// ErrRulesSpies occurs when the spies are not a minority
+	ErrRulesSpies = errors.New("rules spies must be between 1 and less than half of the players")
+	// ErrRulesProposals occurs when the amount of proposals is zero or more than 5
+	ErrRulesProposals = errors.New("rules proposals must be between 1 and 5")
+)
+
+// ParseRuleset parses and validates a ruleset in json form, the same form as rules.json.
+func ParseRuleset(bytes []byte) (Ruleset, error) {
+	rs := Ruleset{}
+
+	err := json.Unmarshal(bytes, &rs)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	err = rs.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("rs.Validate: %w", err)
+	}
+
+	return rs, nil
+}
+
+// mustRuleset is the same as ParseRuleset, only it panics on failure.
+func mustRuleset(bytes []byte) Ruleset {
+	rs, err := ParseRuleset(bytes)
+	if err != nil {
+		panic(err)
+	}
+
+	return rs
+}
+
+// Validate returns an error if any of the rules in the ruleset is invalid.
+func (rs Ruleset) Validate() error {
+	if len(rs) == 0 {
+		return ErrRulesetEmpty
+	}
+
+	for players, r := range rs {
+		err := r.Validate(players)
+		if err != nil {
+			return fmt.Errorf("%d players: %w", players, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate returns an error if the rules are invalid for the amount of players.
+func (r Rules) Validate(players int) error {
+	if players < 5 || players > 10 {
+		return ErrRulesPlayers
+	}
+
+	for k, v := range r.Assignees {
+		if v == 0 || int(v) > players {
+			return fmt.Errorf("round %d: %w", k+1, ErrRulesAssignees)
+		}
+
+		if r.MinFailure[k] == 0 || r.MinFailure[k] > v {
+			return fmt.Errorf("round %d: %w", k+1, ErrRulesMinFailure)
+		}
+	}
+
+	if r.Spies <= 0 || r.Spies*2 >= players {
+		return ErrRulesSpies
+	}
+
+	if r.Proposals <= 0 || r.Proposals > len(Round{}.Missions) {
+		return ErrRulesProposals
+	}
+
+	return nil
+}
+
+// Rounds returns the rounds for a new game.
+func (r Rules) Rounds() (rounds [5]Round) {
+	for k := range rounds {
+		rounds[k] = Round{
+			Assignees:  r.Assignees[k],
+			MinFailure: r.MinFailure[k],
+			Proposals:  uint8(r.Proposals),
+		}
+	}
+
+	return
+}
@@ -0,0 +1,119 @@
+package game
+
+import (
+	"sync"
+
+	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+// seat is a conn.Conn that stays with the player for the whole game, it forwards everything to the player's current connection.
+// It keeps track of the commands that the game added, so they can be added again whenever the player reconnects.
+type seat struct {
+	conn conn.Conn
+	cmd  map[string]conn.MessageStruct
+
+	mtx sync.Mutex
+}
+
+func newSeat(c conn.Conn) *seat {
+	return &seat{
+		conn: c,
+		cmd:  map[string]conn.MessageStruct{},
+	}
+}
+
+// current returns the current connection of the player.
+func (s *seat) current() conn.Conn {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.conn
+}
+
+// rebind replaces the connection of the player, and adds the pending commands to the new connection.
+func (s *seat) rebind(c conn.Conn) {
+	s.mtx.Lock()
+	s.conn = c
+
+	cmd := map[string]conn.MessageStruct{}
+	for group, strct := range s.cmd {
+		cmd[group] = conn.MessageStruct{}
+		for name, callback := range strct {
+			cmd[group][name] = callback
+		}
+	}
+	s.mtx.Unlock()
+
+	for group, strct := range cmd {
+		c.AddCommand(group, strct)
+	}
+}
+
+func (s *seat) AddCommand(group string, strct conn.MessageStruct) {
+	s.mtx.Lock()
+	_, ok := s.cmd[group]
+	if !ok {
+		s.cmd[group] = conn.MessageStruct{}
+	}
+
+	for name, callback := range strct {
+		s.cmd[group][name] = callback
+	}
+	c := s.conn
+	s.mtx.Unlock()
+
+	c.AddCommand(group, strct)
+}
+
+func (s *seat) ExecuteCommand(group, name string, body []byte) error {
+	return s.current().ExecuteCommand(group, name, body)
+}
+
+func (s *seat) RemoveCommandsByGroup(group string) {
+	s.mtx.Lock()
+	delete(s.cmd, group)
+	c := s.conn
+	s.mtx.Unlock()
+
+	c.RemoveCommandsByGroup(group)
+}
+
+func (s *seat) RemoveCommandsByNames(group string, names ...string) {
+	s.mtx.Lock()
+	strct, ok := s.cmd[group]
+	if ok {
+		for _, name := range names {
+			delete(strct, name)
+		}
+	}
+	c := s.conn
+	s.mtx.Unlock()
+
+	c.RemoveCommandsByNames(group, names...)
+}
+
+func (s *seat) WriteMessage(ms conn.MessageSend) error {
+	return s.current().WriteMessage(ms)
+}
+
+func (s *seat) WriteBytes(body []byte) {
+	s.current().WriteBytes(body)
+}
+
+func (s *seat) GetDone() chan bool {
+	return s.current().GetDone()
+}
+
+func (s *seat) Destroy() {
+	s.current().Destroy()
+}
+
+func (s *seat) GetClient() client.Client {
+	return s.current().GetClient()
+}
+
+func (s *seat) SetLogger(log logger.Logger) {
+	s.current().SetLogger(log)
+}
@@ -0,0 +1,88 @@
+package game
+
+import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math/rand/v2"
+)
+
+// ErrSeed occurs when a seed isn't 32 bytes of hex
+var ErrSeed = errors.New("seed must be 32 bytes of hex")
+
+// Seed is the seed of a game. It's the whole state of a ChaCha8 generator, so the players can't search for it through the seats they see.
+type Seed [32]byte
+
+// String returns the seed in hex.
+func (s Seed) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// MarshalText marshals the seed in hex.
+func (s Seed) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText unmarshals a seed in hex, it returns ErrSeed unless it's 32 bytes.
+func (s *Seed) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil || len(b) != len(s) {
+		return ErrSeed
+	}
+
+	copy(s[:], b)
+	return nil
+}
+
+// Setting is an optional setting of NewGame.
+type Setting func(g *Game)
+
+// WithSeed sets the seed of the game, the same seed with the same players gives the same seats, roles, captains and decks.
+// It's meant for reproducing a bug report or a tournament game, the players must never know the seed before the game is finished.
+func WithSeed(seed Seed) Setting {
+	return func(g *Game) {
+		g.Seed = &seed
+	}
+}
+
+// WithSeats sets the order in which the players sit, instead of shuffling them. The seats need to be the ids of every player, see ErrSeats.
+// It's meant for rematches, where the same table plays again.
+func WithSeats(seats []string) Setting {
+	return func(g *Game) {
+		g.order = append([]string{}, seats...)
+	}
+}
+
+// cryptoSource is a rand.Source that reads from crypto/rand, it's used by games without a seed.
+type cryptoSource struct{}
+
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	// crypto/rand.Read never returns an error
+	crand.Read(b[:])
+
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// newID returns a random id for a game, it doesn't come from the seed so two games with the same seed don't share an id.
+func newID() string {
+	var b [8]byte
+	crand.Read(b[:])
+
+	return hex.EncodeToString(b[:])
+}
+
+// newRand sets up the random number generators of the game. Games without a seed use crypto/rand, so their roles can't be reproduced or guessed.
+// The seats are public, so they never come from the generator that assigns the roles.
+func (g *Game) newRand() {
+	if g.Seed == nil {
+		g.rand = rand.New(cryptoSource{})
+		g.seatRand = rand.New(cryptoSource{})
+		return
+	}
+
+	g.rand = rand.New(rand.NewChaCha8(*g.Seed))
+	g.seatRand = rand.New(rand.NewChaCha8(sha256.Sum256(append([]byte("seats"), g.Seed[:]...))))
+}
@@ -0,0 +1,215 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+)
+
+var (
+	// ErrSpectator occurs when the client is already a player or a spectator in the game
+	ErrSpectator = errors.New("client is already in the game")
+	// ErrCasterDelay occurs when a caster is added with a delay of zero or less
+	ErrCasterDelay = errors.New("caster delay must be more than zero")
+)
+
+// spectator is a client that watches the game without a seat. Spectators get every broadcast, and a game.get that's masked as if they were resistance.
+// The game never adds any command to a spectator, so they can't take part in the game.
+type spectator struct {
+	conn.Conn
+	// delay is only set for casters, who see every role but only after the delay, so they can't leak the roles to the players.
+	delay time.Duration
+	// messages that are waiting for the delay
+	queue chan delayed
+	done  chan bool
+}
+
+// delayed is a message that's sent to a caster once the delay has passed.
+type delayed struct {
+	at   time.Time
+	body []byte
+}
+
+// AddSpectator adds a client that watches the game as if they were resistance, without being able to play.
+func (g *Game) AddSpectator(c conn.Conn) error {
+	return g.addSpectator(&spectator{Conn: c})
+}
+
+// AddCaster adds an omniscient spectator, they see every role but every message gets to them after the delay.
+func (g *Game) AddCaster(c conn.Conn, delay time.Duration) error {
+	if delay <= 0 {
+		return ErrCasterDelay
+	}
+
+	return g.addSpectator(&spectator{
+		Conn:  c,
+		delay: delay,
+		queue: make(chan delayed, 1024),
+		done:  make(chan bool),
+	})
+}
+
+func (g *Game) addSpectator(s *spectator) error {
+	id := s.GetClient().ID
+	if _, ok := g.Players[id]; ok {
+		return ErrSpectator
+	}
+
+	masked, revealed := g.spectatorGets()
+
+	g.smtx.Lock()
+	defer g.smtx.Unlock()
+
+	if _, ok := g.spectators[id]; ok {
+		return ErrSpectator
+	}
+
+	if g.spectators == nil {
+		g.spectators = map[string]*spectator{}
+	}
+	g.spectators[id] = s
+
+	if s.delay > 0 {
+		go s.run(s.queue)
+	}
+
+	// the spectator gets the game as it is right now
+	g.sendSpectator(s, masked, revealed)
+
+	g.log.Debug("g.addSpectator: %s", id)
+	return nil
+}
+
+// RemoveSpectator removes a spectator from the game, casters don't get the messages that were waiting for the delay.
+func (g *Game) RemoveSpectator(id string) {
+	g.smtx.Lock()
+	defer g.smtx.Unlock()
+
+	s, ok := g.spectators[id]
+	if !ok {
+		return
+	}
+
+	if s.done != nil {
+		close(s.done)
+	}
+	delete(g.spectators, id)
+}
+
+// closeSpectators is called once the game is finished, casters still get the messages that are waiting for the delay.
+func (g *Game) closeSpectators() {
+	g.smtx.Lock()
+	defer g.smtx.Unlock()
+
+	for _, s := range g.spectators {
+		if s.queue != nil {
+			close(s.queue)
+			s.queue = nil
+		}
+	}
+}
+
+// broadcastSpectators sends the body to every spectator, it's called by Broadcast.
+func (g *Game) broadcastSpectators(body []byte) {
+	g.smtx.Lock()
+	defer g.smtx.Unlock()
+
+	for _, s := range g.spectators {
+		g.writeSpectator(s, body)
+	}
+}
+
+// sendSpectators sends the game information to every spectator, it's called by Send.
+func (g *Game) sendSpectators() {
+	g.smtx.Lock()
+	watched := len(g.spectators) > 0
+	g.smtx.Unlock()
+
+	// the game doesn't get marshaled for no one
+	if !watched {
+		return
+	}
+
+	masked, revealed := g.spectatorGets()
+
+	g.smtx.Lock()
+	defer g.smtx.Unlock()
+
+	for _, s := range g.spectators {
+		g.sendSpectator(s, masked, revealed)
+	}
+}
+
+// sendSpectator sends the game information to a single spectator, casters get the revealed game. g.smtx needs to be locked before calling it.
+func (g *Game) sendSpectator(s *spectator, masked, revealed []byte) {
+	if s.delay > 0 {
+		g.writeSpectator(s, revealed)
+	} else {
+		g.writeSpectator(s, masked)
+	}
+}
+
+// spectatorGets returns the game.get of the spectators, that's masked as if they were resistance, and the game.get of the casters, that reveals every role.
+func (g *Game) spectatorGets() (masked, revealed []byte) {
+	// an empty id makes sure nothing gets revealed to the spectator
+	masked = g.marshalGet(g.mask("", PlayerTypeResistance))
+
+	// the game goroutine changes the game under g.mtx, so the casters' view gets marshaled under it as well
+	g.mtx.Lock()
+	revealed = g.marshalGet(g.view(g.Players))
+	g.mtx.Unlock()
+
+	return masked, revealed
+}
+
+// marshalGet returns the game.get message of the view.
+func (g *Game) marshalGet(v view) []byte {
+	bytes, err := json.Marshal(conn.MessageSend{
+		Group: "game",
+		Name:  "get",
+		Body:  v,
+	})
+	if err != nil {
+		g.log.Warn("json.Marshal: %v", err)
+		return nil
+	}
+
+	return bytes
+}
+
+// writeSpectator sends the body to a spectator right away, or after the delay for casters. g.smtx needs to be locked before calling it.
+func (g *Game) writeSpectator(s *spectator, body []byte) {
+	if s.delay == 0 {
+		s.WriteBytes(body)
+		return
+	}
+
+	// the game is finished
+	if s.queue == nil {
+		return
+	}
+
+	// never block the game, a caster that's that far behind just misses the message
+	select {
+	case s.queue <- delayed{at: time.Now(), body: body}:
+	default:
+		g.log.Warn("caster %s queue is full, dropping message", s.GetClient().ID)
+	}
+}
+
+// run sends the messages of a caster once their delay passes, until the caster gets removed or every message has been sent.
+func (s *spectator) run(queue chan delayed) {
+	for d := range queue {
+		timer := time.NewTimer(time.Until(d.at.Add(s.delay)))
+
+		select {
+		case <-s.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.WriteBytes(d.body)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package game
+
+import (
+	"errors"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+)
+
+const (
+	// VoteNone doesn't count a missed vote, it's the default.
+	VoteNone VotePolicy = iota
+	// VoteApprove counts a missed vote as an approval.
+	VoteApprove
+	// VoteReject counts a missed vote as a rejection.
+	VoteReject
+)
+
+// VotePolicy is what happens whenever a player doesn't vote in time.
+type VotePolicy uint8
+
+const (
+	// DecideSuccess counts a missed mission card as a success.
+	DecideSuccess DecidePolicy = iota
+	// DecideFail counts a missed mission card of a spy as a failure, the resistance can only succeed so their missed cards are still a success.
+	DecideFail
+)
+
+// DecidePolicy is what happens whenever an assignee doesn't decide in time.
+type DecidePolicy uint8
+
+// Policies are the timeout policies of the game, they fire whenever a player does nothing in a phase.
+// A captain that doesn't choose in time always gets a random team.
+type Policies struct {
+	Vote   VotePolicy   `json:"vote"`
+	Decide DecidePolicy `json:"decide"`
+}
+
+// Timeout is the body of game.timeout, it's broadcasted whenever a policy fires.
+type Timeout struct {
+	// the phase that timed out, i.e choose, vote or decide
+	Phase string `json:"phase"`
+	// the round and mission in which the phase timed out
+	Round   int `json:"round"`
+	Mission int `json:"mission"`
+	// players that did nothing
+	// by id
+	Players []string `json:"players"`
+}
+
+// ErrPolicies occurs when one of the policies is unknown
+var ErrPolicies = errors.New("game timeout policies are not valid")
+
+// Validate returns ErrPolicies if one of the policies is unknown.
+func (p Policies) Validate() error {
+	if p.Vote > VoteReject || p.Decide > DecideFail {
+		return ErrPolicies
+	}
+
+	return nil
+}
+
+// SetPolicies sets the timeout policies of the game, it needs to be called before Game.Run.
+func (g *Game) SetPolicies(p Policies) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	g.policies = p
+	return nil
+}
+
+// broadcastTimeout informs the players that a policy fired.
+func (g *Game) broadcastTimeout(t Timeout) {
+	g.log.Debug("timeout: %s %v", t.Phase, t.Players)
+
+	g.Broadcast(conn.MessageSend{
+		Group: "game",
+		Name:  "timeout",
+		Body:  t,
+	})
+}
+
+// timeoutChoose picks a random team whenever the captain didn't choose in time.
+func (g *Game) timeoutChoose(ri, mi int) {
+	g.mtx.Lock()
+	if len(g.Rounds[ri].Missions[mi].Assignees) > 0 {
+		g.mtx.Unlock()
+		return
+	}
+
+	ids := []string{}
+	for _, k := range g.rand.Perm(len(g.Seats))[:g.Rounds[ri].Assignees] {
+		ids = append(ids, g.Seats[k])
+	}
+
+	g.Rounds[ri].Missions[mi].Assignees = ids
+	captain := g.captain
+	g.mtx.Unlock()
+
+	g.broadcastTimeout(Timeout{
+		Phase:   "choose",
+		Round:   ri,
+		Mission: mi,
+		Players: []string{captain},
+	})
+}
+
+// timeoutVote counts the votes of the players that didn't vote in time, by the vote policy.
+func (g *Game) timeoutVote(ri, mi int) {
+	g.mtx.Lock()
+	mission := &g.Rounds[ri].Missions[mi]
+
+	voted := map[string]bool{}
+	for _, id := range mission.Accept {
+		voted[id] = true
+	}
+	for _, id := range mission.Decline {
+		voted[id] = true
+	}
+
+	missed := []string{}
+	for _, id := range g.Seats {
+		if voted[id] || !g.Players[id].IsValid() {
+			continue
+		}
+
+		missed = append(missed, id)
+		if g.policies.Vote == VoteNone {
+			continue
+		}
+
+		accept := g.policies.Vote == VoteApprove
+		if accept {
+			mission.Accept = append(mission.Accept, id)
+		} else {
+			mission.Decline = append(mission.Decline, id)
+		}
+
+		g.record(Event{
+			Type:    EventVote,
+			Round:   ri,
+			Mission: mi,
+			Player:  id,
+			Accept:  accept,
+		})
+	}
+	g.mtx.Unlock()
+
+	// VoteNone didn't count anything, so no policy fired
+	if len(missed) == 0 || g.policies.Vote == VoteNone {
+		return
+	}
+
+	g.broadcastTimeout(Timeout{
+		Phase:   "vote",
+		Round:   ri,
+		Mission: mi,
+		Players: missed,
+	})
+}
+
+// timeoutDecide counts the cards of the assignees that didn't decide in time, by the decide policy.
+func (g *Game) timeoutDecide(ri, mi int) {
+	g.mtx.Lock()
+	round := &g.Rounds[ri]
+
+	decided := map[string]bool{}
+	for _, id := range round.success {
+		decided[id] = true
+	}
+	for _, id := range round.failure {
+		decided[id] = true
+	}
+
+	missed := []string{}
+	for _, id := range round.Missions[mi].Assignees {
+		if decided[id] {
+			continue
+		}
+
+		missed = append(missed, id)
+		success := g.policies.Decide != DecideFail || !g.Players[id].Type.IsSpy()
+		if success {
+			round.success = append(round.success, id)
+		} else {
+			round.failure = append(round.failure, id)
+		}
+
+		g.record(Event{
+			Type:    EventCard,
+			Round:   ri,
+			Mission: mi,
+			Player:  id,
+			Success: success,
+		})
+	}
+	g.mtx.Unlock()
+
+	if len(missed) == 0 {
+		return
+	}
+
+	g.broadcastTimeout(Timeout{
+		Phase:   "decide",
+		Round:   ri,
+		Mission: mi,
+		Players: missed,
+	})
+}
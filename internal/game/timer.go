@@ -0,0 +1,96 @@
+package game
+
+import (
+	"context"
+	"time"
+)
+
+// Timers is the duration of every phase in the game, a zero duration means the phase never times out.
+type Timers struct {
+	// the captain choosing the assignees
+	Choose time.Duration `json:"choose"`
+	// the players voting on the assignees
+	Vote time.Duration `json:"vote"`
+	// the assignees deciding the result of the mission
+	Decide time.Duration `json:"decide"`
+	// the captain giving the dealt plot cards, only in TypeOriginal
+	Deal time.Duration `json:"deal"`
+	// the players reacting to an accepted mission with plot cards, only in TypeOriginal
+	React time.Duration `json:"react"`
+	// the holder of the lady of the lake checking a player, only with OptionLady
+	Lady time.Duration `json:"lady"`
+	// the captain investigating a player, only in TypeHunter
+	Investigate time.Duration `json:"investigate"`
+	// the spies assassinating merlin, only in TypeAvalon
+	Assassinate time.Duration `json:"assassinate"`
+	// the hunter accusing a chief, only in TypeHunter
+	Hunt time.Duration `json:"hunt"`
+}
+
+const (
+	// TimersBlitz is the name of the blitz preset, for fast paced games.
+	TimersBlitz = "blitz"
+	// TimersStandard is the name of the standard preset, it's the default preset.
+	TimersStandard = "standard"
+	// TimersCasual is the name of the casual preset, for games where the players talk a lot.
+	TimersCasual = "casual"
+	// TimersUnlimited is the name of the unlimited preset, no phase ever times out.
+	TimersUnlimited = "unlimited"
+)
+
+// TimerPresets is a map containing Timers represented by the name of the preset.
+var TimerPresets = map[string]Timers{
+	TimersBlitz: {
+		Choose:      time.Second * 20,
+		Vote:        time.Second * 30,
+		Decide:      time.Second * 10,
+		Deal:        time.Second * 20,
+		React:       time.Second * 5,
+		Lady:        time.Second * 20,
+		Investigate: time.Second * 20,
+		Assassinate: time.Second * 30,
+		Hunt:        time.Second * 30,
+	},
+	TimersStandard: {
+		Choose:      time.Minute * 1,
+		Vote:        time.Minute * 3,
+		Decide:      time.Second * 30,
+		Deal:        time.Minute * 1,
+		React:       time.Second * 15,
+		Lady:        time.Minute * 1,
+		Investigate: time.Minute * 1,
+		Assassinate: time.Minute * 1,
+		Hunt:        time.Minute * 1,
+	},
+	TimersCasual: {
+		Choose:      time.Minute * 5,
+		Vote:        time.Minute * 10,
+		Decide:      time.Minute * 2,
+		Deal:        time.Minute * 3,
+		React:       time.Minute * 1,
+		Lady:        time.Minute * 3,
+		Investigate: time.Minute * 3,
+		Assassinate: time.Minute * 5,
+		Hunt:        time.Minute * 5,
+	},
+	TimersUnlimited: {},
+}
+
+// SetTimers sets the duration of every phase in the game, it needs to be called before Game.Run.
+func (g *Game) SetTimers(t Timers) {
+	g.timers = t
+}
+
+// newPhase returns a context that's done whenever the phase times out, along with the deadline of the phase.
+// the deadline is nil if d is zero, which means the phase only ends through cancel.
+func newPhase(d time.Duration) (context.Context, context.CancelFunc, *time.Time) {
+	if d <= 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		return ctx, cancel, nil
+	}
+
+	deadline := time.Now().Add(d)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+
+	return ctx, cancel, &deadline
+}
@@ -3,30 +3,41 @@ package lobby
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/fatih/color"
-	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/bot"
 	"github.com/toms1441/resistance-server/internal/conn"
 	"github.com/toms1441/resistance-server/internal/game"
 	"github.com/toms1441/resistance-server/internal/logger"
 )
 
+// ownerCommands are the commands that only the owner gets, they move along with the lobby in Lobby.Transfer.
+var ownerCommands = []string{"kick", "unban", "transfer", "addbot", "removebot", "invite", "rotate", "update", "readycheck", "start", "rematch"}
+
+// memberCommands are the commands that every member gets, they're removed once the member leaves.
+var memberCommands = []string{"leave", "get", "ready"}
+
+// addCommands adds the lobby commands to a member, the owner gets ownerCommands as well.
 func (l *Lobby) addCommands(c conn.Conn) {
 	cl := c.GetClient()
 	if !cl.IsValid() {
 		return
 	}
 
-	c.RemoveCommandsByGroup("lobby")
+	// the member might've been the owner
+	c.RemoveCommandsByNames("lobby", ownerCommands...)
 
 	strct := conn.MessageStruct{
 		"leave": func(log logger.Logger, bytes []byte) error {
-			return l.Leave(c)
+			return l.leave(c)
 		},
 
 		"get": func(log logger.Logger, bytes []byte) error {
-			return c.WriteMessage(l.MessageSend())
+			return c.WriteMessage(l.messageSend())
 		},
+
+		"ready": l.readyCommand(c),
 	}
 
 	if len(l.conns) == 0 {
@@ -34,54 +45,371 @@ func (l *Lobby) addCommands(c conn.Conn) {
 	}
 
 	tempcl, ok := l.conns[cl.ID]
-	if ok && tempcl == c {
+	if ok && tempcl == c && cl.ID == l.Owner {
 		strct["kick"] = func(log logger.Logger, bytes []byte) error {
-			target := client.Client{}
-			json.Unmarshal(bytes, &target)
-			if len(target.ID) > 0 {
-				// if the target is a valid client
-				targetconn, ok := l.conns[target.ID]
-				if !ok {
-					return fmt.Errorf("invalid client")
+			k := Kick{}
+			err := json.Unmarshal(bytes, &k)
+			if err != nil {
+				return fmt.Errorf("json.Unmarshal: %v", err)
+			}
+
+			return l.kick(k)
+		}
+
+		strct["unban"] = func(log logger.Logger, bytes []byte) error {
+			var id string
+			err := json.Unmarshal(bytes, &id)
+			if err != nil {
+				return fmt.Errorf("json.Unmarshal: %v", err)
+			}
+
+			return l.unban(id)
+		}
+
+		strct["transfer"] = func(log logger.Logger, bytes []byte) error {
+			var id string
+			err := json.Unmarshal(bytes, &id)
+			if err != nil {
+				return fmt.Errorf("json.Unmarshal: %v", err)
+			}
+
+			return l.transfer(id)
+		}
+
+		strct["addbot"] = func(log logger.Logger, bytes []byte) error {
+			name := bot.StrategyCautious
+			if len(bytes) > 0 {
+				err := json.Unmarshal(bytes, &name)
+				if err != nil {
+					return fmt.Errorf("json.Unmarshal: %v", err)
 				}
+			}
 
-				l.Leave(targetconn)
+			strategy, ok := bot.Strategies[name]
+			if !ok {
+				return ErrStrategy
+			}
+
+			if l.GetState() != StateWaiting {
+				return ErrState
+			}
+
+			if l.Timers == game.TimersUnlimited {
+				return ErrBotTimers
+			}
+
+			b := bot.NewBot(strategy)
+			b.SetLogger(l.log)
+
+			err := l.join(b)
+			if err != nil {
+				b.Destroy()
+				return err
 			}
 
 			return nil
 		}
 
-		lobbylog := l.log
-		strct["start"] = func(log logger.Logger, bytes []byte) error {
-			gameoption := game.OptionNone
+		strct["removebot"] = func(log logger.Logger, bytes []byte) error {
+			var id string
+			err := json.Unmarshal(bytes, &id)
+			if err != nil {
+				return fmt.Errorf("json.Unmarshal: %v", err)
+			}
+
+			if l.GetState() != StateWaiting {
+				return ErrState
+			}
+
+			b, ok := l.conns[id].(*bot.Bot)
+			if !ok {
+				return fmt.Errorf("invalid bot")
+			}
+
+			err = l.leave(b)
+			if err != nil {
+				return fmt.Errorf("l.leave: %w", err)
+			}
+
+			b.Destroy()
+			return nil
+		}
 
-			err := json.Unmarshal(bytes, &gameoption)
+		strct["invite"] = func(log logger.Logger, bytes []byte) error {
+			return c.WriteMessage(conn.MessageSend{
+				Group: "lobby",
+				Name:  "invite",
+				Body:  l.invite,
+			})
+		}
+
+		strct["rotate"] = func(log logger.Logger, bytes []byte) error {
+			return c.WriteMessage(conn.MessageSend{
+				Group: "lobby",
+				Name:  "invite",
+				Body:  l.rotateInvite(),
+			})
+		}
+
+		strct["update"] = func(log logger.Logger, bytes []byte) error {
+			// fields that aren't in the body keep their value
+			settings := struct {
+				Type       Type
+				Private    bool
+				Option     game.Option
+				Timers     string
+				MaxPlayers int
+			}{l.Type, l.Private, l.Option, l.Timers, l.MaxPlayers}
+
+			err := json.Unmarshal(bytes, &settings)
 			if err != nil {
 				return fmt.Errorf("json.Unmarshal: %v", err)
 			}
 
-			lc := logger.DefaultConfig
-			lc.PAttr = color.New(color.FgYellow, color.Italic)
-			lc.Prefix = "game"
-			lc.Suffix = l.log.GetSuffix()
-			lc.Debug = true
+			if l.GetState() != StateWaiting {
+				return ErrState
+			}
+
+			temp := &Lobby{
+				Type:       settings.Type,
+				Private:    settings.Private,
+				Timers:     settings.Timers,
+				Policies:   l.Policies,
+				BestOf:     l.BestOf,
+				Option:     settings.Option,
+				MaxPlayers: settings.MaxPlayers,
+				maxClient:  l.maxClient,
+			}
 
-			g, err := game.NewGame(l.conns, l.Type.Common(), gameoption)
+			err = temp.Validate()
 			if err != nil {
-				return fmt.Errorf("game.NewGame: %w", err)
+				return fmt.Errorf("l.Validate: %w", err)
 			}
-			g.SetLogger(logger.NewLogger(lc))
 
-			go func(g *game.Game) {
-				s := make(chan game.Status)
-				go g.Run(s)
-				lobbylog.Info("g.Run: %v", <-s)
-			}(g)
+			if temp.MaxPlayers != 0 && len(l.conns) > temp.MaxPlayers {
+				return ErrMaxPlayers
+			}
 
-			return nil
+			if temp.Timers == game.TimersUnlimited {
+				for _, v := range l.conns {
+					if _, ok := v.(*bot.Bot); ok {
+						return ErrBotTimers
+					}
+				}
+			}
+
+			l.Type, l.Private, l.Option, l.Timers, l.MaxPlayers = temp.Type, temp.Private, temp.Option, temp.Timers, temp.MaxPlayers
+
+			l.notify(l.update, c)
+
+			// a bigger lobby has seats for the waitlist
+			l.promote()
+
+			l.log.Debug("l.update: %s %s %s", l.Type, l.Option, l.Timers)
+			return l.send()
+		}
+
+		strct["readycheck"] = func(log logger.Logger, bytes []byte) error {
+			return l.startReadyCheck()
+		}
+
+		strct["start"] = func(log logger.Logger, bytes []byte) error {
+			// the body is optional, the options that were set by lobby.update get used without it
+			gameoption := l.Option
+			if len(bytes) > 0 {
+				err := json.Unmarshal(bytes, &gameoption)
+				if err != nil {
+					return fmt.Errorf("json.Unmarshal: %v", err)
+				}
+			}
+
+			if l.GetState() != StateWaiting {
+				return ErrState
+			}
+
+			// people that are away don't get to ruin the game
+			err := l.checkReady()
+			if err != nil {
+				return err
+			}
+
+			// only one game at a time
+			err = l.setState(StateStarting, StateWaiting)
+			if err != nil {
+				return err
+			}
+
+			// the players see the options of the game that's running
+			l.Option = gameoption
+
+			// a new game starts a new series
+			l.Series = newSeries(l.BestOf)
+
+			return l.startGame(gameoption)
+		}
+
+		strct["rematch"] = func(log logger.Logger, bytes []byte) error {
+			if l.table == nil {
+				return ErrTable
+			}
+
+			if !l.table.same(l.conns) {
+				return ErrTable
+			}
+
+			if state := l.GetState(); state != StateWaiting && state != StatePostGame {
+				return ErrState
+			}
+
+			err := l.checkReady()
+			if err != nil {
+				return err
+			}
+
+			// the players don't have to wait for the end of the post game
+			err = l.setState(StateStarting, StateWaiting, StatePostGame)
+			if err != nil {
+				return err
+			}
+
+			l.smtx.Lock()
+			if l.postTimer != nil {
+				l.postTimer.Stop()
+			}
+			l.smtx.Unlock()
+
+			if l.Series == nil || l.Series.IsOver() {
+				l.Series = newSeries(l.BestOf)
+			}
+
+			return l.startGame(l.table.option, game.WithSeats(l.table.seats))
+		}
+
+		// the lobby might've changed hands since the commands were added
+		for _, name := range ownerCommands {
+			fn := strct[name]
+			strct[name] = func(log logger.Logger, bytes []byte) error {
+				if c.GetClient().ID != l.Owner {
+					return ErrNotOwner
+				}
+
+				return fn(log, bytes)
+			}
+		}
+	}
+
+	// the commands run one at a time, along with the connections and the game
+	for name, fn := range strct {
+		fn := fn
+		strct[name] = func(log logger.Logger, bytes []byte) error {
+			l.mtx.Lock()
+			defer l.unlock()
+
+			return fn(log, bytes)
 		}
 	}
 
 	c.AddCommand("lobby", strct)
 
 }
+
+// startGame starts a game with every client in the lobby, the lobby needs to be in StateStarting and l.mtx needs to be locked.
+// Once the game is finished the lobby stays in StatePostGame for Config.PostGame, then goes back to StateWaiting.
+func (l *Lobby) startGame(gameoption game.Option, settings ...game.Setting) error {
+	lobbylog := l.log
+
+	lc := logger.DefaultConfig
+	lc.PAttr = color.New(color.FgYellow, color.Italic)
+	lc.Prefix = "game"
+	lc.Suffix = l.log.GetSuffix()
+	lc.Debug = true
+
+	rules := l.rules
+	if rules == nil {
+		rules = game.DefaultRuleset
+	}
+
+	if l.seed != nil {
+		settings = append(settings, game.WithSeed(*l.seed))
+	}
+
+	g, err := game.NewGameWithRuleset(l.conns, l.Type.Common(), gameoption, rules, settings...)
+	if err != nil {
+		l.setState(StateWaiting, StateStarting)
+		return fmt.Errorf("game.NewGame: %w", err)
+	}
+	g.SetLogger(logger.NewLogger(lc))
+
+	timers := l.Timers
+	if len(timers) == 0 {
+		timers = game.TimersStandard
+	}
+	g.SetTimers(game.TimerPresets[timers])
+
+	err = g.SetPolicies(l.Policies)
+	if err != nil {
+		l.setState(StateWaiting, StateStarting)
+		return fmt.Errorf("g.SetPolicies: %w", err)
+	}
+
+	for _, c := range l.spectators {
+		err = l.spectateGame(g, c)
+		if err != nil {
+			lobbylog.Warn("l.spectateGame: %v", err)
+		}
+	}
+
+	// the table gets reused by lobby.rematch
+	l.table = newTable(g)
+
+	l.game = g
+	err = l.setState(StateInGame, StateStarting)
+	if err != nil {
+		lobbylog.Warn("l.setState: %v", err)
+	}
+
+	go func(g *game.Game) {
+		s := make(chan game.Status)
+		go g.Run(s)
+
+		status := <-s
+		lobbylog.Info("g.Run: %v", status)
+
+		if l.replays != nil {
+			_, err := l.replays.CreateReplay(g)
+			if err != nil {
+				lobbylog.Warn("l.replays.CreateReplay: %v", err)
+			}
+		}
+
+		l.mtx.Lock()
+		defer l.unlock()
+
+		if l.Series != nil {
+			l.Series.add(status, g.GetWinners())
+		}
+
+		err := l.setState(StatePostGame, StateInGame)
+		if err != nil {
+			lobbylog.Warn("l.setState: %v", err)
+		}
+
+		// the game is over, so the clients that dropped actually leave
+		l.endGame()
+
+		// the players get some time to look at the end of the game, unless they start a rematch
+		l.smtx.Lock()
+		l.postTimer = time.AfterFunc(l.postGame, func() {
+			l.mtx.Lock()
+			defer l.unlock()
+
+			l.setState(StateWaiting, StatePostGame)
+		})
+		l.smtx.Unlock()
+
+		// clients that dropped mid-game left seats for the waitlist
+		l.promote()
+	}(g)
+
+	return nil
+}
@@ -2,25 +2,49 @@ package lobby
 
 import (
 	"errors"
+	"fmt"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/game"
 )
 
+// RulesetOfficial is the name of the official ruleset, it's used whenever a lobby doesn't pick a ruleset.
+const RulesetOfficial = "official"
+
 type Config struct {
 	IDLen     int `validate:"required"`
 	MaxClient int `validate:"required"`
+	// Rulesets are house rules that lobbies can pick by name, a ruleset named official replaces the official ruleset.
+	Rulesets map[string]game.Ruleset
+	// CasterDelay is how far behind the casters watch the games, casters see every role so the delay keeps them from leaking the roles. Zero means lobbies don't allow casters.
+	CasterDelay time.Duration
+	// PostGame is how long a lobby stays in StatePostGame after a game, so the players can look at the end of the game before the next one starts.
+	PostGame time.Duration
+	// ReadyCheck is how long the members of a lobby have to answer a ready check, games only start once every member is ready. Zero means games start without a ready check.
+	ReadyCheck time.Duration
 	// this field is composed of idlen
 	max int
 	// this field is also composed of idlen
 	min int
+	// this field is composed of game.DefaultRuleset and rulesets
+	rulesets map[string]game.Ruleset
 }
 
 var DefaultConfig = Config{
-	IDLen:     4,
-	MaxClient: 10,
+	IDLen:       4,
+	MaxClient:   10,
+	Rulesets:    map[string]game.Ruleset{},
+	CasterDelay: time.Minute * 2,
+	PostGame:    time.Second * 10,
+	ReadyCheck:  time.Second * 30,
 }
 
 var (
 	ErrMaxClientZero = errors.New("Maxclient cannot be zero or less")
 	ErrIDLengthZero  = errors.New("ID Length cannot be zero or less")
+	ErrCasterDelay   = errors.New("Caster delay cannot be less than zero")
+	ErrPostGame      = errors.New("Post game cannot be less than zero")
+	ErrReadyCheck    = errors.New("Ready check cannot be less than zero")
 )
 
 func (c Config) Validate() error {
@@ -32,5 +56,23 @@ func (c Config) Validate() error {
 		return ErrIDLengthZero
 	}
 
+	if c.CasterDelay < 0 {
+		return ErrCasterDelay
+	}
+
+	if c.PostGame < 0 {
+		return ErrPostGame
+	}
+
+	if c.ReadyCheck < 0 {
+		return ErrReadyCheck
+	}
+
+	for name, rs := range c.Rulesets {
+		if err := rs.Validate(); err != nil {
+			return fmt.Errorf("ruleset %s: %w", name, err)
+		}
+	}
+
 	return nil
 }
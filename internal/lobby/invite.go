@@ -0,0 +1,81 @@
+package lobby
+
+import (
+	crand "crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrAccess if a client wants to join a private lobby without its invite or its password
+var ErrAccess = errors.New("Lobby is private, it needs an invite or a password")
+
+// newInvite returns a random invite token, it's long enough that it can't be guessed like the lobby id.
+func newInvite() string {
+	var b [16]byte
+	crand.Read(b[:])
+
+	return hex.EncodeToString(b[:])
+}
+
+// SetPassword sets the password of a private lobby, an empty password means the lobby can only be joined with its invite.
+// It's not a part of the lobby's json, so clients can't see it.
+func (l *Lobby) SetPassword(password string) {
+	l.password = password
+}
+
+// GetInvite returns the invite token of the lobby, it's only sent to the owner.
+func (l *Lobby) GetInvite() string {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	return l.invite
+}
+
+// RotateInvite replaces the invite token of the lobby, the old token stops working.
+func (l *Lobby) RotateInvite() string {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	return l.rotateInvite()
+}
+
+// rotateInvite replaces the invite token of the lobby, l.mtx needs to be locked.
+func (l *Lobby) rotateInvite() string {
+	l.invite = newInvite()
+	return l.invite
+}
+
+// Authorize returns ErrAccess if the client can't join the lobby. Public lobbies can be joined by anyone, private lobbies need the invite or the password.
+// Clients that are already in the lobby don't need either.
+func (l *Lobby) Authorize(id, invite, password string) error {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	if !l.Private {
+		return nil
+	}
+
+	if _, ok := l.conns[id]; ok {
+		return nil
+	}
+
+	if _, ok := l.spectators[id]; ok {
+		return nil
+	}
+
+	if len(invite) > 0 && equal(invite, l.invite) {
+		return nil
+	}
+
+	if len(password) > 0 && len(l.password) > 0 && equal(password, l.password) {
+		return nil
+	}
+
+	return ErrAccess
+}
+
+// equal compares two secrets in constant time.
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -3,30 +3,105 @@ package lobby
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"sort"
+	"sync"
+	"time"
 
+	"github.com/toms1441/resistance-server/internal/bot"
 	"github.com/toms1441/resistance-server/internal/client"
 	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
 	"github.com/toms1441/resistance-server/internal/logger"
+	"github.com/toms1441/resistance-server/internal/replay"
 	"github.com/toms1441/resistance-server/internal/repo"
 )
 
 // Lobby the lobby that the clients will be in, it gathers maximum of 10 players. and is used later to transform into a Game.
-// The lobby's owner is client with index 0.
+// The lobby's owner is the first client that joins, until they leave or transfer the lobby with lobby.transfer.
 type Lobby struct {
 	ID      string
 	Type    Type
 	Private bool
+	// Ruleset is the name of the ruleset that the game uses, empty means RulesetOfficial
+	Ruleset string
+	// Timers is the name of the timer preset that the game uses, empty means game.TimersStandard
+	Timers string
+	// Policies is what happens whenever a player does nothing in a phase
+	Policies game.Policies
+	// Option is the roles of the next game, the players see them before the game starts
+	Option game.Option
+	// MaxPlayers is the maximum amount of players, between 5 and Config.MaxClient. Zero means Config.MaxClient
+	MaxPlayers int
+	// State is the state of the lobby, see State. It's set by the lobby, not the clients.
+	State State
+	// BestOf is the amount of games in a series, zero means a series goes on until a new one gets started with lobby.start
+	BestOf int
+	// Series is the score of the games that were played in a row with lobby.rematch, nil until the first game starts
+	Series  *Series
 	Clients []client.Client
-	conns   map[string]conn.Conn
-
-	// lobby owner
-	owner string
+	// Spectators watch the lobby and its games without playing, they don't count towards Clients
+	Spectators []client.Client
+	// Ready is whether each member answered the ready check in time, nil until a ready check starts and once a game starts. See Config.ReadyCheck
+	// by id
+	Ready map[string]bool
+	// Owner is the client that gets the owner commands, see Lobby.Transfer
+	// by id
+	Owner string
+	// Banned are the clients that can't join the lobby, see lobby.kick
+	Banned []client.Client
+	// Waitlist are the clients that wait for a seat in a full lobby, in order. See Lobby.Wait
+	Waitlist []client.Client
+	conns    map[string]conn.Conn
+	// waiting is the connections of Waitlist, in the same order
+	waiting []conn.Conn
+	// maxClient is Config.MaxClient, the ceiling of MaxPlayers
+	maxClient int
+	// by id
+	spectators map[string]conn.Conn
+	// spectators that see every role, see game.AddCaster
+	// by id
+	casters map[string]bool
+	// casterDelay is the delay of the casters, zero means casters aren't allowed
+	casterDelay time.Duration
+	// rules is the ruleset that Ruleset refers to
+	rules game.Ruleset
+	// game is the game that's currently running, nil if there's none
+	game *game.Game
+	// postGame is how long the lobby stays in StatePostGame
+	postGame  time.Duration
+	postTimer *time.Timer
+	// readyCheck is how long the members have to answer a ready check, zero means games start without one
+	readyCheck time.Duration
+	// readyTimer ends the ready check, nil when there's no ready check running
+	readyTimer *time.Timer
+	// rmtx is locked whenever Ready is read or changed
+	rmtx sync.Mutex
+	// table is the setup of the last game, see lobby.rematch
+	table *table
+	// invite is the token that lets clients join the lobby while it's private, see Lobby.Authorize
+	invite string
+	// password is an optional alternative to the invite
+	password string
+	// seed is the seed of the next game, nil means a new seed for every game
+	seed *game.Seed
+	// replays is where the replays of finished games get saved, nil means they don't get saved
+	replays replay.Service
+	// clients whose connection closed while the game was running, they keep their seat until the game ends
+	// by id
+	dropped map[string]bool
 
 	insert []chan conn.Conn
 	remove []chan conn.Conn
+	update []chan conn.Conn
+	// notices are sent to the subscribers once l.mtx is unlocked, so they can use the lobby right away
+	notices []notice
 
 	log logger.Logger
+	// mtx is locked whenever the lobby is read or changed by its clients, their connections, the game or the timers. It's locked before smtx and rmtx
+	mtx sync.Mutex
+	// smtx is locked whenever the state is read or changed
+	smtx sync.Mutex
 }
 
 type Clients []client.Client
@@ -58,12 +133,14 @@ func (t Type) Common() uint8 {
 const (
 	// TypeBasic has spies and resistance.
 	TypeBasic Type = iota
-	// TypeOriginal no documentation yet.
+	// TypeOriginal same as TypeBasic only with plot cards.
 	TypeOriginal
 	// TypeAvalon same as TypeBasic only with merlin.
 	// Merlin is a character that sees all spies and resistance.
 	TypeAvalon
-	// TypeHunter no documentation
+	// TypeHunter same as TypeBasic only with chiefs and hunters.
+	// After the 2nd, 3rd and 4th round the captain investigates if a player is a chief.
+	// Whenever a team wins 3 rounds the other team's hunter gets one last chance to win, by finding their chief.
 	TypeHunter
 	// TypeTrumpmode no documentation
 	TypeTrumpmode
@@ -76,38 +153,73 @@ var (
 	ErrType = errors.New("Lobby Type is not valid")
 	// ErrChannel if l.Insert == nil || l.Remove == ninl
 	ErrChannel = errors.New("Lobby channels(l.Insert, l.Remove) are nil.")
+	// ErrTimers if the timer preset isn't in game.TimerPresets
+	ErrTimers = errors.New("Lobby Timers is not a valid preset")
+	// ErrRuleset if the ruleset isn't in Config.Rulesets and isn't RulesetOfficial
+	ErrRuleset = errors.New("Lobby Ruleset does not exist")
+	// ErrStrategy if the bot strategy isn't in bot.Strategies
+	ErrStrategy = errors.New("Bot strategy does not exist")
+	// ErrBotTimers if the lobby has bots and uses game.TimersUnlimited, bots only play choose, vote and decide so the other phases would never end
+	ErrBotTimers = errors.New("Bots can't play without timers")
+	// ErrCaster if a client wants to be a caster in a lobby that doesn't allow casters
+	ErrCaster = errors.New("Lobby does not allow casters")
+	// ErrOption if the option contains a role that doesn't exist
+	ErrOption = errors.New("Lobby Option is not valid")
+	// ErrMaxPlayers if MaxPlayers isn't zero or between 5 and Config.MaxClient, or if there are more players than MaxPlayers
+	ErrMaxPlayers = errors.New("Lobby MaxPlayers is not valid")
 )
 
 // Equal compares two different lobbies
 func (l *Lobby) Equal(l2 *Lobby) bool {
-	ref1 := *l
-	ref2 := *l2
+	if l.ID != l2.ID {
+		return false
+	}
+
+	if l.Type != l2.Type {
+		return false
+	}
+
+	if l.Private != l2.Private {
+		return false
+	}
+
+	if l.Ruleset != l2.Ruleset {
+		return false
+	}
 
-	if ref1.ID != ref2.ID {
+	if l.Timers != l2.Timers {
 		return false
 	}
 
-	if ref1.Type != ref2.Type {
+	if l.Policies != l2.Policies {
 		return false
 	}
 
-	if ref1.Private != ref2.Private {
+	if l.Option != l2.Option {
 		return false
 	}
 
-	if len(ref1.Clients) != len(ref2.Clients) {
+	if l.MaxPlayers != l2.MaxPlayers {
+		return false
+	}
+
+	if l.State != l2.State {
+		return false
+	}
+
+	if len(l.Clients) != len(l2.Clients) {
 		return false
 	}
 
 	id1 := []string{}
-	for _, v := range ref1.Clients {
+	for _, v := range l.Clients {
 		if v.IsValid() {
 			id1 = append(id1, v.ID)
 		}
 	}
 
 	id2 := []string{}
-	for _, v := range ref2.Clients {
+	for _, v := range l2.Clients {
 		if v.IsValid() {
 			id2 = append(id2, v.ID)
 		}
@@ -142,6 +254,14 @@ func (l *Lobby) SetLogger(log logger.Logger) {
 
 // Join inserts a client into the lobby, and updates the rest of the clients.
 func (l *Lobby) Join(c conn.Conn) error {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	return l.join(c)
+}
+
+// join inserts a client into the lobby, l.mtx needs to be locked.
+func (l *Lobby) join(c conn.Conn) error {
 
 	if len(c.GetClient().ID) == 0 {
 		return repo.ErrClientInvalid
@@ -151,6 +271,28 @@ func (l *Lobby) Join(c conn.Conn) error {
 		l.log = logger.NullLogger()
 	}
 
+	if l.isBanned(c.GetClient().ID) {
+		return ErrBanned
+	}
+
+	// clients that join mid-game watch the game instead
+	if _, ok := l.conns[c.GetClient().ID]; !ok && l.GetState().Playing() {
+		return l.spectate(c, false)
+	}
+
+	// a spectator that joins a full lobby keeps watching
+	if _, ok := l.conns[c.GetClient().ID]; !ok && len(l.conns) >= l.capacity() {
+		return ErrLobbyFull
+	}
+
+	// a client that joins leaves the waitlist
+	l.unwait(c.GetClient().ID)
+
+	// a spectator that joins becomes a player
+	if _, ok := l.spectators[c.GetClient().ID]; ok {
+		l.unspectate(c.GetClient().ID)
+	}
+
 	_, ok := l.conns[c.GetClient().ID]
 	if !ok {
 
@@ -159,42 +301,30 @@ func (l *Lobby) Join(c conn.Conn) error {
 		}
 
 		l.conns[c.GetClient().ID] = c
-		if l.GetClientIndex(c.GetClient().ID) == -1 {
+		if l.getClientIndex(c.GetClient().ID) == -1 {
 			l.Clients = append(l.Clients, c.GetClient())
 		}
 
-		for _, v := range l.insert {
-			v <- c
-		}
+		l.joinReadyCheck(c)
 
-		keys := []string{}
-		for _, v := range l.conns {
-			keys = append(keys, v.GetClient().ID)
-		}
-		sort.Strings(keys)
+		l.notify(l.insert, c)
 
 		sort.Slice(l.Clients, func(i, j int) bool {
 			return l.Clients[i].ID < l.Clients[j].ID
 		})
 
-		if l.owner == "" {
-			l.owner = keys[0]
+		// the first client that joins owns the lobby, or the first one after it was left without an owner
+		if l.Owner == "" {
+			l.Owner = c.GetClient().ID
 		}
+		l.addCommands(c)
 
 		l.log.Debug("l.Join: %v", c.GetClient().ID)
 
 		// When the connection closes, remove the lobby.
-		go func(l *Lobby, c conn.Conn) {
-			for {
-				select {
-				case <-c.GetDone():
-					l.Leave(c)
-					return
-				}
-			}
-		}(l, c)
+		go l.watch(c)
 
-		err := l.Send()
+		err := l.send()
 		if err != nil {
 			l.log.Debug("l.Send: %v", err)
 			return err
@@ -208,6 +338,14 @@ func (l *Lobby) Join(c conn.Conn) error {
 
 // Leave removes a client from the lobby, and updates the rest of the clients.
 func (l *Lobby) Leave(c conn.Conn) error {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	return l.leave(c)
+}
+
+// leave removes a client from the lobby, l.mtx needs to be locked.
+func (l *Lobby) leave(c conn.Conn) error {
 
 	if l.log == nil {
 		l.log = logger.NullLogger()
@@ -217,7 +355,37 @@ func (l *Lobby) Leave(c conn.Conn) error {
 		return repo.ErrClientInvalid
 	}
 
-	if i := l.GetClientIndex(c.GetClient().ID); i >= 0 {
+	if _, ok := l.spectators[c.GetClient().ID]; ok {
+		l.unspectate(c.GetClient().ID)
+
+		l.log.Debug("l.Remove: spectator %v", c.GetClient().ID)
+		return l.send()
+	}
+
+	if l.getWaitingIndex(c.GetClient().ID) >= 0 {
+		l.unwait(c.GetClient().ID)
+
+		l.log.Debug("l.Remove: waiting %v", c.GetClient().ID)
+		return l.send()
+	}
+
+	// a connection that got replaced by Lobby.Rebind doesn't take the seat with it
+	if v, ok := l.conns[c.GetClient().ID]; ok && v != c {
+		return repo.ErrClient404
+	}
+
+	// players that leave mid-game keep their seat, they leave once the game ends
+	if _, ok := l.conns[c.GetClient().ID]; ok && l.GetState().Playing() {
+		if l.dropped == nil {
+			l.dropped = map[string]bool{}
+		}
+
+		l.dropped[c.GetClient().ID] = true
+		l.log.Debug("l.Remove: %s dropped mid-game", c.GetClient().ID)
+		return nil
+	}
+
+	if i := l.getClientIndex(c.GetClient().ID); i >= 0 {
 		// order matters
 		l.Clients = append(l.Clients[:i], l.Clients[i+1:]...)
 	}
@@ -232,27 +400,35 @@ func (l *Lobby) Leave(c conn.Conn) error {
 		// it's just easier to copy the value and replace
 		// https://stackoverflow.com/questions/37334119/how-to-delete-an-element-from-a-slice-in-golang
 		delete(l.conns, c.GetClient().ID)
+		l.leaveReadyCheck(c.GetClient().ID)
+
+		c.RemoveCommandsByNames("lobby", append(memberCommands, ownerCommands...)...)
+
+		// the lobby goes to the first client by id, bots can't own a lobby. Without anyone left the next client that joins owns it
+		if c.GetClient().ID == l.Owner {
+			l.Owner = ""
 
-		// double-check cause we might've removed the last player
-		if len(l.conns) > 0 {
 			id := []string{}
-			for k := range l.conns {
-				id = append(id, k)
+			for k, v := range l.conns {
+				if _, ok := v.(*bot.Bot); !ok {
+					id = append(id, k)
+				}
 			}
 			sort.Strings(id)
 
-			firstc := l.conns[id[0]]
-			if firstc.GetClient().IsValid() {
-				l.addCommands(firstc)
+			if len(id) > 0 {
+				l.Owner = id[0]
+				l.addCommands(l.conns[id[0]])
 			}
 		}
 
-		for _, v := range l.remove {
-			v <- c
-		}
+		l.notify(l.remove, c)
+
+		// the seat goes to the first client in the waitlist
+		l.promote()
 
 		l.log.Debug("l.Remove: %v", c.GetClient().ID)
-		err := l.Send()
+		err := l.send()
 		l.log.Debug("l.Send: %v", err)
 		if err != nil {
 			return err
@@ -264,8 +440,206 @@ func (l *Lobby) Leave(c conn.Conn) error {
 	return repo.ErrClient404
 }
 
+// watch removes the client from the lobby whenever the connection closes.
+// While a game is running the client keeps their seat instead, so they can reconnect through Lobby.Rebind. See Lobby.Leave
+func (l *Lobby) watch(c conn.Conn) {
+	<-c.GetDone()
+
+	l.mtx.Lock()
+	defer l.unlock()
+
+	id := c.GetClient().ID
+	// spectators don't have a seat to keep
+	if l.spectators[id] == c {
+		l.leave(c)
+		return
+	}
+
+	if i := l.getWaitingIndex(id); i >= 0 && l.waiting[i] == c {
+		l.leave(c)
+		return
+	}
+
+	// the client already reconnected, so this connection got replaced
+	if l.conns[id] != c {
+		return
+	}
+
+	l.leave(c)
+}
+
+// Rebind binds a returning client's new connection to the lobby, and to the game if there's one running.
+// The old connection gets replaced right away, so it can't take the seat with it once it closes.
+func (l *Lobby) Rebind(c conn.Conn) error {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	id := c.GetClient().ID
+	if _, ok := l.conns[id]; !ok {
+		return repo.ErrClient404
+	}
+
+	l.conns[id] = c
+	delete(l.dropped, id)
+	go l.watch(c)
+
+	l.addCommands(c)
+
+	if l.game != nil {
+		err := l.game.Rebind(c)
+		if err != nil {
+			return fmt.Errorf("l.game.Rebind: %w", err)
+		}
+	}
+
+	l.log.Debug("l.Rebind: %s", id)
+	return c.WriteMessage(l.messageSend())
+}
+
+// Spectate adds a client to the lobby as a spectator, they get every lobby update and watch the games without playing.
+// Casters see every role in the game, but only after the lobby's caster delay.
+func (l *Lobby) Spectate(c conn.Conn, caster bool) error {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	return l.spectate(c, caster)
+}
+
+// spectate adds a client to the lobby as a spectator, l.mtx needs to be locked.
+func (l *Lobby) spectate(c conn.Conn, caster bool) error {
+	id := c.GetClient().ID
+	if len(id) == 0 {
+		return repo.ErrClientInvalid
+	}
+
+	if l.log == nil {
+		l.log = logger.NullLogger()
+	}
+
+	if _, ok := l.conns[id]; ok {
+		return repo.ErrClientExists
+	}
+
+	if _, ok := l.spectators[id]; ok {
+		return repo.ErrClientExists
+	}
+
+	if l.isBanned(id) {
+		return ErrBanned
+	}
+
+	if caster && l.casterDelay <= 0 {
+		return ErrCaster
+	}
+
+	if l.spectators == nil {
+		l.spectators = map[string]conn.Conn{}
+		l.casters = map[string]bool{}
+	}
+
+	l.spectators[id] = c
+	l.casters[id] = caster
+
+	l.Spectators = append(l.Spectators, c.GetClient())
+	sort.Slice(l.Spectators, func(i, j int) bool {
+		return l.Spectators[i].ID < l.Spectators[j].ID
+	})
+
+	if l.game != nil {
+		err := l.spectateGame(l.game, c)
+		if err != nil {
+			l.unspectate(id)
+			return fmt.Errorf("l.spectateGame: %w", err)
+		}
+	}
+
+	go l.watch(c)
+
+	l.log.Debug("l.Spectate: %v", id)
+	return l.send()
+}
+
+// spectateGame adds a spectator to the game, as a caster if they're one.
+func (l *Lobby) spectateGame(g *game.Game, c conn.Conn) error {
+	if l.casters[c.GetClient().ID] {
+		return g.AddCaster(c, l.casterDelay)
+	}
+
+	return g.AddSpectator(c)
+}
+
+// unspectate removes a spectator from the lobby and the game.
+func (l *Lobby) unspectate(id string) {
+	delete(l.spectators, id)
+	delete(l.casters, id)
+
+	for k, v := range l.Spectators {
+		if v.ID == id {
+			l.Spectators = append(l.Spectators[:k], l.Spectators[k+1:]...)
+			break
+		}
+	}
+
+	if l.game != nil {
+		l.game.RemoveSpectator(id)
+	}
+}
+
+// SetSeed sets the seed of the games in the lobby, it's meant for admins reproducing a game.
+// It's not a part of the lobby's json, so clients can't set it or see it.
+func (l *Lobby) SetSeed(seed game.Seed) {
+	l.seed = &seed
+}
+
+// endGame is called whenever the game is finished, clients that dropped mid-game leave the lobby. l.mtx needs to be locked.
+func (l *Lobby) endGame() {
+	l.game = nil
+
+	for id := range l.dropped {
+		c, ok := l.conns[id]
+		if ok {
+			l.leave(c)
+		}
+	}
+	l.dropped = nil
+}
+
+// notice is a client that gets sent to the subscribers of a channel.
+type notice struct {
+	subscribers []chan conn.Conn
+	c           conn.Conn
+}
+
+// notify queues a client for the subscribers, l.mtx needs to be locked.
+func (l *Lobby) notify(subscribers []chan conn.Conn, c conn.Conn) {
+	if len(subscribers) == 0 {
+		return
+	}
+
+	l.notices = append(l.notices, notice{
+		subscribers: append([]chan conn.Conn{}, subscribers...),
+		c:           c,
+	})
+}
+
+// unlock unlocks l.mtx, then sends the queued notices. The subscribers might lock the lobby, so they can't get them while it's locked.
+func (l *Lobby) unlock() {
+	notices := l.notices
+	l.notices = nil
+	l.mtx.Unlock()
+
+	for _, n := range notices {
+		for _, v := range n.subscribers {
+			v <- n.c
+		}
+	}
+}
+
 // SubscribeInsert returns a channel that gets set whenever a client joins
 func (l *Lobby) SubscribeInsert() (insert chan conn.Conn) {
+	l.mtx.Lock()
+	defer l.unlock()
+
 	insert = make(chan conn.Conn)
 	if l.insert == nil {
 		l.insert = []chan conn.Conn{}
@@ -275,8 +649,25 @@ func (l *Lobby) SubscribeInsert() (insert chan conn.Conn) {
 	return insert
 }
 
+// SubscribeUpdate returns a channel that gets set whenever the owner updates the settings of the lobby
+func (l *Lobby) SubscribeUpdate() (update chan conn.Conn) {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	update = make(chan conn.Conn)
+	if l.update == nil {
+		l.update = []chan conn.Conn{}
+	}
+
+	l.update = append(l.update, update)
+	return update
+}
+
 // SubscribeRemove returns a channel that gets set whenever a client leaves
 func (l *Lobby) SubscribeRemove() (remove chan conn.Conn) {
+	l.mtx.Lock()
+	defer l.unlock()
+
 	remove = make(chan conn.Conn)
 	if l.remove == nil {
 		l.remove = []chan conn.Conn{}
@@ -304,16 +695,30 @@ func (l *Lobby) removesubscribe(slice []chan conn.Conn, chcon chan conn.Conn) []
 }
 
 func (l *Lobby) RemoveSubscribeInsert(insert chan conn.Conn) {
+	l.mtx.Lock()
+	defer l.unlock()
+
 	l.insert = l.removesubscribe(l.insert, insert)
 }
 
 func (l *Lobby) RemoveSubscribeRemove(remove chan conn.Conn) {
+	l.mtx.Lock()
+	defer l.unlock()
+
 	l.remove = l.removesubscribe(l.remove, remove)
 }
 
 // Send send the clients information about the lobby, it's called whenever a client joins or leaves the lobby.
 func (l *Lobby) Send() error {
-	bytes, err := json.Marshal(l.MessageSend())
+	l.mtx.Lock()
+	defer l.unlock()
+
+	return l.send()
+}
+
+// send sends the clients information about the lobby, l.mtx needs to be locked.
+func (l *Lobby) send() error {
+	bytes, err := json.Marshal(l.messageSend())
 
 	if err != nil {
 		l.log.Debug("l.Send: %v", err)
@@ -324,11 +729,27 @@ func (l *Lobby) Send() error {
 		v.WriteBytes(bytes)
 	}
 
+	for _, v := range l.spectators {
+		v.WriteBytes(bytes)
+	}
+
+	for _, v := range l.waiting {
+		v.WriteBytes(bytes)
+	}
+
 	return nil
 }
 
 // GetClientIndex returns the client index by id.
-func (l *Lobby) GetClientIndex(id string) (i int) {
+func (l *Lobby) GetClientIndex(id string) int {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	return l.getClientIndex(id)
+}
+
+// getClientIndex returns the client index by id, l.mtx needs to be locked.
+func (l *Lobby) getClientIndex(id string) (i int) {
 	i = -1
 
 	for k, v := range l.Clients {
@@ -343,13 +764,86 @@ func (l *Lobby) GetClientIndex(id string) (i int) {
 
 // MessageSend is a method that returns conn.MessageSend
 func (l *Lobby) MessageSend() conn.MessageSend {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	return l.messageSend()
+}
+
+// messageSend returns conn.MessageSend with a snapshot of the lobby, l.mtx needs to be locked.
+func (l *Lobby) messageSend() conn.MessageSend {
 	return conn.MessageSend{
 		Group: "lobby",
 		Name:  "get",
-		Body:  l,
+		Body:  l.snapshot(),
 	}
 }
 
+// Snapshot is a copy of the exported fields of a lobby, it can be marshalled while the lobby changes.
+type Snapshot struct {
+	ID         string
+	Type       Type
+	Private    bool
+	Ruleset    string
+	Timers     string
+	Policies   game.Policies
+	Option     game.Option
+	MaxPlayers int
+	State      State
+	BestOf     int
+	Series     *Series
+	Clients    []client.Client
+	Spectators []client.Client
+	Ready      map[string]bool
+	Owner      string
+	Banned     []client.Client
+	Waitlist   []client.Client
+}
+
+// Snapshot returns a copy of the exported fields of the lobby.
+func (l *Lobby) Snapshot() Snapshot {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	return l.snapshot()
+}
+
+// snapshot returns a copy of the exported fields of the lobby, l.mtx needs to be locked.
+func (l *Lobby) snapshot() Snapshot {
+	s := Snapshot{
+		ID:         l.ID,
+		Type:       l.Type,
+		Private:    l.Private,
+		Ruleset:    l.Ruleset,
+		Timers:     l.Timers,
+		Policies:   l.Policies,
+		Option:     l.Option,
+		MaxPlayers: l.MaxPlayers,
+		State:      l.State,
+		BestOf:     l.BestOf,
+		Clients:    append([]client.Client(nil), l.Clients...),
+		Spectators: append([]client.Client(nil), l.Spectators...),
+		Ready:      l.GetReady(),
+		Owner:      l.Owner,
+		Banned:     append([]client.Client(nil), l.Banned...),
+		Waitlist:   append([]client.Client(nil), l.Waitlist...),
+	}
+
+	if l.Series != nil {
+		s.Series = l.Series.copy()
+	}
+
+	return s
+}
+
+// MarshalJSON marshals a snapshot of the lobby, so the lobby can be marshalled while it changes.
+func (l *Lobby) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Snapshot())
+}
+
+// options is every role that a lobby can have
+const options = game.OptionPercival | game.OptionMorgana | game.OptionAssassin | game.OptionLady | game.OptionOberon | game.OptionMordred | game.OptionLancelot
+
 // Validate validates the lobby, it includes ID and Type validators as well as validate.Struct(lobby)
 func (l *Lobby) Validate() (err error) {
 
@@ -358,5 +852,32 @@ func (l *Lobby) Validate() (err error) {
 		return ErrType
 	}
 
+	if len(l.Timers) > 0 {
+		if _, ok := game.TimerPresets[l.Timers]; !ok {
+			return ErrTimers
+		}
+	}
+
+	if err := l.Policies.Validate(); err != nil {
+		return err
+	}
+
+	if l.BestOf < 0 {
+		return ErrBestOf
+	}
+
+	if l.Option&^options != 0 {
+		return ErrOption
+	}
+
+	// i.e the avalon roles need TypeAvalon
+	if err := l.Option.Validate(l.Type.Common()); err != nil {
+		return err
+	}
+
+	if l.MaxPlayers != 0 && (l.MaxPlayers < 5 || l.MaxPlayers > l.ceiling()) {
+		return ErrMaxPlayers
+	}
+
 	return
 }
@@ -0,0 +1,153 @@
+package lobby
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/repo"
+)
+
+var (
+	// ErrBanned if a client that got banned from the lobby wants to join it
+	ErrBanned = errors.New("Client is banned from the lobby")
+	// ErrOwner if the owner wants to kick themselves
+	ErrOwner = errors.New("Lobby owner can't kick themselves")
+	// ErrNotOwner if a client that isn't the owner uses an owner command
+	ErrNotOwner = errors.New("Only the lobby owner can do that")
+)
+
+// Kick is the body of lobby.kick, Reason gets sent to the kicked client in lobby.kick. Banned clients can't come back until the owner unbans them.
+type Kick struct {
+	client.Client
+	Reason string
+	Ban    bool
+}
+
+// GetOwner returns the id of the owner.
+func (l *Lobby) GetOwner() string {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	return l.Owner
+}
+
+// Transfer makes another member the owner of the lobby, the owner commands move along with it.
+func (l *Lobby) Transfer(id string) error {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	return l.transfer(id)
+}
+
+// transfer makes another member the owner of the lobby, l.mtx needs to be locked.
+func (l *Lobby) transfer(id string) error {
+	c, ok := l.conns[id]
+	if !ok {
+		return repo.ErrClient404
+	}
+
+	if id == l.Owner {
+		return nil
+	}
+
+	old, ok := l.conns[l.Owner]
+	l.Owner = id
+
+	// the old owner gets the commands of a member
+	if ok {
+		l.addCommands(old)
+	}
+	l.addCommands(c)
+
+	l.log.Debug("l.Transfer: %s", id)
+	return l.send()
+}
+
+// kick removes a member, spectator or waiting client from the lobby. The client gets lobby.kick with the reason.
+func (l *Lobby) kick(k Kick) error {
+	id := k.ID
+	if id == l.Owner {
+		return ErrOwner
+	}
+
+	c, ok := l.conns[id]
+	// players can't be kicked out of a game
+	if ok && l.GetState().Playing() {
+		return ErrState
+	}
+
+	if !ok {
+		c, ok = l.spectators[id]
+	}
+
+	if !ok {
+		if i := l.getWaitingIndex(id); i >= 0 {
+			c, ok = l.waiting[i], true
+		}
+	}
+
+	if !ok {
+		return repo.ErrClient404
+	}
+
+	if k.Ban {
+		l.ban(c.GetClient())
+	}
+
+	c.WriteMessage(conn.MessageSend{
+		Group: "lobby",
+		Name:  "kick",
+		Body:  k.Reason,
+	})
+
+	l.log.Debug("l.kick: %s %s", id, k.Reason)
+	err := l.leave(c)
+	if err != nil {
+		return err
+	}
+
+	// nobody else plays with the bot, just like lobby.removebot
+	if b, ok := c.(*bot.Bot); ok {
+		b.Destroy()
+	}
+
+	return nil
+}
+
+// isBanned returns a boolean value representing if the client is banned from the lobby.
+func (l *Lobby) isBanned(id string) bool {
+	for _, v := range l.Banned {
+		if v.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ban adds a client to the ban list.
+func (l *Lobby) ban(cl client.Client) {
+	if l.isBanned(cl.ID) {
+		return
+	}
+
+	l.Banned = append(l.Banned, cl)
+	sort.Slice(l.Banned, func(i, j int) bool {
+		return l.Banned[i].ID < l.Banned[j].ID
+	})
+}
+
+// unban removes a client from the ban list.
+func (l *Lobby) unban(id string) error {
+	for k, v := range l.Banned {
+		if v.ID == id {
+			l.Banned = append(l.Banned[:k], l.Banned[k+1:]...)
+			return l.send()
+		}
+	}
+
+	return repo.ErrClient404
+}
@@ -0,0 +1,192 @@
+package lobby
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/logger"
+	"github.com/toms1441/resistance-server/internal/repo"
+)
+
+var (
+	// ErrNotReady if the owner starts a game before every member answered the ready check
+	ErrNotReady = errors.New("Lobby members are not ready")
+	// ErrNoReadyCheck if a member answers a ready check that isn't running
+	ErrNoReadyCheck = errors.New("Lobby has no ready check running")
+)
+
+// startReadyCheck starts a ready check, every member has Config.ReadyCheck to answer lobby.ready. Bots are always ready.
+// Once the time is up the clients get lobby.unready with the members that weren't ready, and the check is over. The answers are kept until the game starts.
+func (l *Lobby) startReadyCheck() error {
+	if state := l.GetState(); state != StateWaiting && state != StatePostGame {
+		return ErrState
+	}
+
+	l.rmtx.Lock()
+	if l.readyTimer != nil {
+		l.readyTimer.Stop()
+	}
+
+	l.Ready = map[string]bool{}
+	for id, c := range l.conns {
+		_, l.Ready[id] = c.(*bot.Bot)
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(l.readyCheck, func() {
+		l.mtx.Lock()
+		defer l.unlock()
+
+		unready, ok := l.expireReadyCheck(timer)
+		if !ok {
+			return
+		}
+
+		if len(unready) > 0 {
+			l.sendUnready(unready)
+		}
+
+		l.send()
+	})
+	l.readyTimer = timer
+	l.rmtx.Unlock()
+
+	l.log.Debug("l.startReadyCheck: %v", l.readyCheck)
+	return l.send()
+}
+
+// GetReady returns whether each member answered the ready check in time, nil until a ready check starts and once a game starts.
+func (l *Lobby) GetReady() map[string]bool {
+	l.rmtx.Lock()
+	defer l.rmtx.Unlock()
+
+	if l.Ready == nil {
+		return nil
+	}
+
+	ready := map[string]bool{}
+	for k, v := range l.Ready {
+		ready[k] = v
+	}
+
+	return ready
+}
+
+// joinReadyCheck adds a member that joined in the middle of a ready check.
+func (l *Lobby) joinReadyCheck(c conn.Conn) {
+	l.rmtx.Lock()
+	defer l.rmtx.Unlock()
+
+	if l.Ready != nil {
+		_, l.Ready[c.GetClient().ID] = c.(*bot.Bot)
+	}
+}
+
+// leaveReadyCheck removes a member that left in the middle of a ready check.
+func (l *Lobby) leaveReadyCheck(id string) {
+	l.rmtx.Lock()
+	defer l.rmtx.Unlock()
+
+	delete(l.Ready, id)
+}
+
+// setReady marks a member as ready, as long as the ready check is running.
+func (l *Lobby) setReady(id string) error {
+	l.rmtx.Lock()
+	if l.readyTimer == nil {
+		l.rmtx.Unlock()
+		return ErrNoReadyCheck
+	}
+
+	l.Ready[id] = true
+	l.rmtx.Unlock()
+
+	return l.send()
+}
+
+// readyCommand returns lobby.ready, every member gets it.
+func (l *Lobby) readyCommand(c conn.Conn) conn.MessageCallback {
+	return func(log logger.Logger, bytes []byte) error {
+		id := c.GetClient().ID
+		if l.conns[id] != c {
+			return repo.ErrClient404
+		}
+
+		return l.setReady(id)
+	}
+}
+
+// unready returns the members that haven't answered the ready check, sorted by id.
+func (l *Lobby) unready() []string {
+	ids := []string{}
+	for id := range l.conns {
+		if !l.Ready[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// expireReadyCheck ends the ready check that the timer belongs to, it returns the members that weren't ready.
+// The answers are kept, so checkReady only counts the members that didn't answer in time. ok is false if the ready check already ended.
+func (l *Lobby) expireReadyCheck(timer *time.Timer) (unready []string, ok bool) {
+	l.rmtx.Lock()
+	defer l.rmtx.Unlock()
+
+	if l.readyTimer != timer {
+		return nil, false
+	}
+
+	l.readyTimer = nil
+	return l.unready(), true
+}
+
+// checkReady returns ErrNotReady unless every member answered the ready check, lobbies that don't need a ready check are always ready.
+// It ends the ready check either way, so every game needs a new one. The clients get lobby.unready with the members that weren't ready.
+func (l *Lobby) checkReady() error {
+	if l.readyCheck <= 0 {
+		return nil
+	}
+
+	l.rmtx.Lock()
+	if l.readyTimer != nil {
+		l.readyTimer.Stop()
+		l.readyTimer = nil
+	}
+
+	// without a ready check no one is ready
+	unready := l.unready()
+	l.Ready = nil
+	l.rmtx.Unlock()
+
+	if len(unready) == 0 {
+		return nil
+	}
+
+	l.sendUnready(unready)
+	l.send()
+
+	return ErrNotReady
+}
+
+// sendUnready sends lobby.unready to every client in the lobby.
+func (l *Lobby) sendUnready(ids []string) {
+	ms := conn.MessageSend{
+		Group: "lobby",
+		Name:  "unready",
+		Body:  ids,
+	}
+
+	for _, v := range l.conns {
+		v.WriteMessage(ms)
+	}
+
+	for _, v := range l.spectators {
+		v.WriteMessage(ms)
+	}
+}
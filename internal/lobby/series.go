@@ -0,0 +1,102 @@
+package lobby
+
+import (
+	"errors"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+var (
+	// ErrTable if there's no game to rematch, or the players changed since the last game
+	ErrTable = errors.New("Lobby players changed since the last game")
+	// ErrBestOf if the amount of games in a series is less than zero
+	ErrBestOf = errors.New("Lobby BestOf cannot be less than zero")
+)
+
+// Series is the score of the games that the same table played in a row. It starts with lobby.start, and goes on with every lobby.rematch.
+type Series struct {
+	// BestOf is the amount of games in the series, zero means the series goes on until lobby.start is called
+	BestOf int
+	// Games is the amount of games that have been played in the series
+	Games int
+	// the amount of games that each team won
+	Resistance int
+	Spies      int
+	// Wins is the amount of games that each player won, the roles change every game so the players are scored instead of the teams
+	// by id
+	Wins map[string]int
+}
+
+func newSeries(bestof int) *Series {
+	return &Series{
+		BestOf: bestof,
+		Wins:   map[string]int{},
+	}
+}
+
+// add counts a finished game in the series.
+func (s *Series) add(status game.Status, winners []string) {
+	s.Games++
+
+	switch status {
+	case game.StatusWon:
+		s.Resistance++
+	case game.StatusLost:
+		s.Spies++
+	}
+
+	for _, id := range winners {
+		s.Wins[id]++
+	}
+}
+
+// copy returns a copy of the series, Wins included.
+func (s *Series) copy() *Series {
+	c := *s
+	c.Wins = map[string]int{}
+	for k, v := range s.Wins {
+		c.Wins[k] = v
+	}
+
+	return &c
+}
+
+// IsOver returns a boolean value representing if the series is decided, either a team won most of the games or every game has been played.
+func (s *Series) IsOver() bool {
+	if s.BestOf <= 0 {
+		return false
+	}
+
+	return s.Games >= s.BestOf || s.Resistance > s.BestOf/2 || s.Spies > s.BestOf/2
+}
+
+// table is the setup of the last game, lobby.rematch starts a new game with it.
+type table struct {
+	option game.Option
+	// the order in which the players sat
+	// by id
+	seats []string
+}
+
+func newTable(g *game.Game) *table {
+	return &table{
+		option: g.Option,
+		seats:  append([]string{}, g.Seats...),
+	}
+}
+
+// same returns a boolean value representing if the conns are the players that sat at the table.
+func (t *table) same(conns map[string]conn.Conn) bool {
+	if len(conns) != len(t.seats) {
+		return false
+	}
+
+	for _, id := range t.seats {
+		if _, ok := conns[id]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -9,7 +9,10 @@ import (
 	"time"
 
 	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
 	"github.com/toms1441/resistance-server/internal/logger"
+	"github.com/toms1441/resistance-server/internal/replay"
+	"github.com/toms1441/resistance-server/internal/repo"
 )
 
 // Service is a service that uses the repo in-order to do database actions.
@@ -22,18 +25,23 @@ type Service interface {
 	GetLobbyByID(id string) (*Lobby, error)
 	// GetAllLobbies returns all lobbies
 	GetAllLobbies() ([]*Lobby, error)
+	// GetLobbyByInvite returns the lobby that the invite token belongs to
+	GetLobbyByInvite(invite string) (*Lobby, error)
 	// GetLobbyByClientID returns a lobby by a client's id.
 	// GetLobbyByClientID(id string) (*Lobby, error)
 	// UpdateLobby updates a lobby by it's ID. Note: if you want to update a lobby you need to get it first then update that single field.
 	UpdateLobby(id string, lobby *Lobby) error
 	// RemoveLobby removes a lobby by it's ID.
 	RemoveLobby(id string) error
+	// SetReplayService sets the service that the replays of finished games get saved to.
+	SetReplayService(rserv replay.Service)
 }
 
 type service struct {
-	repo   Repository
-	config Config
-	log    logger.Logger
+	repo    Repository
+	config  Config
+	replays replay.Service
+	log     logger.Logger
 }
 
 var ErrNil = errors.New("lobby is nil")
@@ -83,6 +91,13 @@ func NewService(repo Repository, config Config) (Service, error) {
 	}
 	config.min = min
 
+	config.rulesets = map[string]game.Ruleset{
+		RulesetOfficial: game.DefaultRuleset,
+	}
+	for name, rs := range config.Rulesets {
+		config.rulesets[name] = rs
+	}
+
 	return &service{
 		repo:   repo,
 		config: config,
@@ -120,12 +135,35 @@ func (s *service) CreateLobby(l *Lobby) error {
 	}
 
 	l.ID = strconv.Itoa(id)
+	// MaxPlayers is validated against it
+	l.maxClient = s.config.MaxClient
 
 	err := l.Validate()
 	if err != nil {
 		return fmt.Errorf("l.Validate: %w", err)
 	}
 
+	if len(l.Ruleset) == 0 {
+		l.Ruleset = RulesetOfficial
+	}
+
+	rules, ok := s.config.rulesets[l.Ruleset]
+	if !ok {
+		return ErrRuleset
+	}
+	l.rules = rules
+	l.replays = s.replays
+	l.casterDelay = s.config.CasterDelay
+	l.postGame = s.config.PostGame
+	l.readyCheck = s.config.ReadyCheck
+	l.invite = newInvite()
+	// the state and the series are set by the lobby, not the clients
+	l.State = StateWaiting
+	l.Series = nil
+	l.Ready = nil
+	l.Owner = ""
+	l.Banned = nil
+
 	err = s.repo.Create(l)
 	if err != nil {
 		return fmt.Errorf("repo.Create: %w", err)
@@ -145,7 +183,11 @@ func (s *service) CreateLobby(l *Lobby) error {
 			select {
 			case c := <-r:
 				// in-case there are no players left destroy the lobby
-				if len(l.conns) == 0 {
+				l.mtx.Lock()
+				empty := len(l.conns) == 0
+				l.mtx.Unlock()
+
+				if empty {
 					//s.log.Debug("l.Clients == 0")
 					err := s.RemoveLobby(c.GetClient().ID)
 					if err != nil {
@@ -184,6 +226,24 @@ func (s *service) GetAllLobbies() ([]*Lobby, error) {
 	return l, fmt.Errorf("repo.GetAll: %w", err)
 }
 
+// GetLobbyByInvite returns a lobby by it's invite token.
+func (s *service) GetLobbyByInvite(invite string) (*Lobby, error) {
+	ls, err := s.repo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetAll: %w", err)
+	}
+
+	if len(invite) > 0 {
+		for _, l := range ls {
+			if equal(l.GetInvite(), invite) {
+				return l, nil
+			}
+		}
+	}
+
+	return nil, repo.ErrLobby404
+}
+
 /*
 func (s *service) GetLobbyByClientID(id string) (*Lobby, error) {
 	_, err := s.repo.GetAll()
@@ -214,3 +274,8 @@ func (s *service) RemoveLobby(id string) error {
 
 	return fmt.Errorf("repo.Update: %w", err)
 }
+
+// SetReplayService sets the service that the replays of finished games get saved to, lobbies that are created afterwards use it.
+func (s *service) SetReplayService(rserv replay.Service) {
+	s.replays = rserv
+}
@@ -0,0 +1,77 @@
+package lobby
+
+import (
+	"errors"
+)
+
+const (
+	// StateWaiting is the state of a lobby that's waiting for the owner to start a game.
+	StateWaiting State = iota
+	// StateStarting is the state of a lobby whose game is being set up.
+	StateStarting
+	// StateInGame is the state of a lobby whose game is running. Clients that join become spectators, and players that leave keep their seat until the game ends.
+	StateInGame
+	// StatePostGame is the state of a lobby whose game just finished, it goes back to StateWaiting after Config.PostGame.
+	StatePostGame
+)
+
+// State is the state of the lobby, it moves from StateWaiting to StateStarting to StateInGame to StatePostGame and back to StateWaiting.
+type State uint8
+
+var stateStrings = map[State]string{
+	StateWaiting:  "Waiting",
+	StateStarting: "Starting",
+	StateInGame:   "In game",
+	StatePostGame: "Post game",
+}
+
+func (s State) String() string {
+	val, ok := stateStrings[s]
+	if !ok {
+		return ""
+	}
+
+	return val
+}
+
+// ErrState if the lobby can't do that in its current state
+var ErrState = errors.New("Lobby can't do that in its current state")
+
+// Playing returns a boolean value representing if the game has started, i.e the lobby is in StateStarting or StateInGame.
+func (s State) Playing() bool {
+	return s == StateStarting || s == StateInGame
+}
+
+// GetState returns the state of the lobby.
+func (l *Lobby) GetState() State {
+	l.smtx.Lock()
+	defer l.smtx.Unlock()
+
+	return l.State
+}
+
+// setState moves the lobby to the state, as long as it's in one of the from states. The clients get the lobby whenever the state changes.
+// l.mtx needs to be locked.
+func (l *Lobby) setState(to State, from ...State) error {
+	l.smtx.Lock()
+
+	ok := false
+	for _, v := range from {
+		if l.State == v {
+			ok = true
+			break
+		}
+	}
+
+	if !ok {
+		l.smtx.Unlock()
+		return ErrState
+	}
+
+	l.State = to
+	l.smtx.Unlock()
+
+	l.log.Debug("l.setState: %v", to)
+
+	return l.send()
+}
@@ -0,0 +1,104 @@
+package lobby
+
+import (
+	"errors"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/logger"
+	"github.com/toms1441/resistance-server/internal/repo"
+)
+
+// ErrLobbyFull if a client wants to join a lobby that has as many players as it can have, see Lobby.Wait
+var ErrLobbyFull = errors.New("Lobby is full")
+
+// capacity returns the maximum amount of players in the lobby, it's Lobby.MaxPlayers or the ceiling of Config.MaxClient.
+func (l *Lobby) capacity() int {
+	if l.MaxPlayers > 0 {
+		return l.MaxPlayers
+	}
+
+	return l.ceiling()
+}
+
+// ceiling returns Config.MaxClient, or 10 for lobbies that weren't created through the service.
+func (l *Lobby) ceiling() int {
+	if l.maxClient > 0 {
+		return l.maxClient
+	}
+
+	return 10
+}
+
+// Wait adds a client to the waitlist of a full lobby, they get every lobby update and join the lobby whenever a seat frees up.
+func (l *Lobby) Wait(c conn.Conn) error {
+	l.mtx.Lock()
+	defer l.unlock()
+
+	id := c.GetClient().ID
+	if len(id) == 0 {
+		return repo.ErrClientInvalid
+	}
+
+	if l.log == nil {
+		l.log = logger.NullLogger()
+	}
+
+	if _, ok := l.conns[id]; ok {
+		return repo.ErrClientExists
+	}
+
+	if l.getWaitingIndex(id) >= 0 {
+		return repo.ErrClientExists
+	}
+
+	if l.isBanned(id) {
+		return ErrBanned
+	}
+
+	l.waiting = append(l.waiting, c)
+	l.Waitlist = append(l.Waitlist, c.GetClient())
+
+	go l.watch(c)
+
+	l.log.Debug("l.Wait: %v", id)
+
+	// the seat might've freed up in the meantime
+	l.promote()
+	return l.send()
+}
+
+// getWaitingIndex returns the position of the client in the waitlist, -1 if they're not waiting.
+func (l *Lobby) getWaitingIndex(id string) int {
+	for k, v := range l.waiting {
+		if v.GetClient().ID == id {
+			return k
+		}
+	}
+
+	return -1
+}
+
+// unwait removes a client from the waitlist.
+func (l *Lobby) unwait(id string) {
+	i := l.getWaitingIndex(id)
+	if i == -1 {
+		return
+	}
+
+	// order matters
+	l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+	l.Waitlist = append(l.Waitlist[:i], l.Waitlist[i+1:]...)
+}
+
+// promote moves clients from the waitlist into the lobby, as long as there are free seats and no game is running.
+func (l *Lobby) promote() {
+	for len(l.waiting) > 0 && len(l.conns) < l.capacity() && !l.GetState().Playing() {
+		c := l.waiting[0]
+		l.unwait(c.GetClient().ID)
+
+		err := l.join(c)
+		if err != nil {
+			l.log.Warn("l.join: %v", err)
+		}
+	}
+}
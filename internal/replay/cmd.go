@@ -0,0 +1,153 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+// Start is the body of replay.start
+type Start struct {
+	// the id of the game
+	ID string `json:"id"`
+	// the speed relative to the real time of the game, zero means 1
+	Speed float64 `json:"speed"`
+}
+
+// AddCommands adds the replay command group to the connection:
+// replay.start, replay.play, replay.pause, replay.speed, replay.forward, replay.back and replay.stop
+func (s *service) AddCommands(c conn.Conn) {
+	var stream *Stream
+	var mtx sync.Mutex
+
+	// current returns the stream that's playing, or ErrStream if there's none
+	current := func() (*Stream, error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		if stream == nil {
+			return nil, ErrStream
+		}
+
+		return stream, nil
+	}
+
+	// stop the stream whenever the connection closes
+	go func() {
+		<-c.GetDone()
+
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		if stream != nil {
+			stream.Stop()
+		}
+	}()
+
+	c.AddCommand("replay", conn.MessageStruct{
+		"start": func(log logger.Logger, body []byte) error {
+			start := Start{}
+
+			err := json.Unmarshal(body, &start)
+			if err != nil {
+				return fmt.Errorf("json.Unmarshal: %v", err)
+			}
+
+			if start.Speed == 0 {
+				start.Speed = 1
+			}
+
+			r, err := s.GetReplayByID(start.ID)
+			if err != nil {
+				return fmt.Errorf("s.GetReplayByID: %w", err)
+			}
+
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			// only one replay at a time
+			if stream != nil {
+				stream.Stop()
+				stream = nil
+			}
+
+			stream, err = NewStream(c, r, start.Speed)
+			if err != nil {
+				return fmt.Errorf("NewStream: %w", err)
+			}
+
+			return nil
+		},
+
+		"play": func(log logger.Logger, body []byte) error {
+			st, err := current()
+			if err != nil {
+				return err
+			}
+
+			st.Play()
+			return nil
+		},
+
+		"pause": func(log logger.Logger, body []byte) error {
+			st, err := current()
+			if err != nil {
+				return err
+			}
+
+			st.Pause()
+			return nil
+		},
+
+		"speed": func(log logger.Logger, body []byte) error {
+			var speed float64
+
+			err := json.Unmarshal(body, &speed)
+			if err != nil {
+				return fmt.Errorf("json.Unmarshal: %v", err)
+			}
+
+			st, err := current()
+			if err != nil {
+				return err
+			}
+
+			return st.SetSpeed(speed)
+		},
+
+		"forward": func(log logger.Logger, body []byte) error {
+			st, err := current()
+			if err != nil {
+				return err
+			}
+
+			return st.Forward()
+		},
+
+		"back": func(log logger.Logger, body []byte) error {
+			st, err := current()
+			if err != nil {
+				return err
+			}
+
+			return st.Back()
+		},
+
+		"stop": func(log logger.Logger, body []byte) error {
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			if stream == nil {
+				return ErrStream
+			}
+
+			stream.Stop()
+			stream = nil
+
+			return nil
+		},
+	})
+}
@@ -0,0 +1,76 @@
+package replay
+
+import (
+	"errors"
+
+	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+// Replay is a finished game, with every role revealed and every event that happened in it.
+type Replay struct {
+	// ID is the id of the game
+	ID     string      `json:"id"`
+	Type   game.Type   `json:"type"`
+	Option game.Option `json:"option"`
+	Status game.Status `json:"status"`
+	// Seed can be used to reproduce the game, see game.WithSeed. It's nil if the game didn't have a seed
+	Seed *game.Seed `json:"seed,omitempty"`
+	// Clients are the players in the order they sat
+	Clients []client.Client `json:"clients"`
+	// Players contains the final role of each player
+	// by id
+	Players map[string]game.PlayerType `json:"players"`
+	// Events are every state change in the game, see game.Game.Log
+	Events []game.Event `json:"events,omitempty"`
+}
+
+var (
+	// ErrGameRunning occurs when a replay is made out of a game that isn't finished
+	ErrGameRunning = errors.New("game is not finished")
+	// ErrSpeed occurs when the speed of the stream isn't between MinSpeed and MaxSpeed
+	ErrSpeed = errors.New("replay speed is not valid")
+	// ErrStream occurs when a replay command is called before replay.start
+	ErrStream = errors.New("replay has not been started")
+	// ErrStep occurs when stepping past the first or the last event
+	ErrStep = errors.New("replay cannot step any further")
+)
+
+// NewReplay returns the replay of a finished game.
+func NewReplay(g *game.Game) (*Replay, error) {
+	events := g.Log()
+	if len(events) == 0 || events[len(events)-1].Type != game.EventEnd {
+		return nil, ErrGameRunning
+	}
+
+	r := &Replay{
+		ID:      g.ID,
+		Type:    g.Type,
+		Option:  g.Option,
+		Status:  events[len(events)-1].Status,
+		Seed:    g.Seed,
+		Clients: []client.Client{},
+		Players: map[string]game.PlayerType{},
+		Events:  events,
+	}
+
+	for _, id := range g.Seats {
+		p, ok := g.Players[id]
+		if !ok {
+			continue
+		}
+
+		r.Clients = append(r.Clients, p.GetClient())
+		r.Players[id] = p.Type
+	}
+
+	return r, nil
+}
+
+// header returns the replay without the events, the events get sent one at a time by the stream.
+func (r *Replay) header() Replay {
+	h := *r
+	h.Events = nil
+
+	return h
+}
@@ -0,0 +1,11 @@
+package replay
+
+// Repository is the repo in which we store the replays. Replays never change, so it can only Create and Read.
+type Repository interface {
+	// Create inserts a replay into the database.
+	Create(r *Replay) error
+	// GetByID returns a replay by the game's id.
+	GetByID(id string) (*Replay, error)
+	// IsValid returns a boolean value indicating the validity of the repository.
+	IsValid() bool
+}
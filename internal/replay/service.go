@@ -0,0 +1,72 @@
+package replay
+
+import (
+	"fmt"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+// Service is a service that uses the repo in-order to do database actions.
+type Service interface {
+	// SetLogger sets the logger for the service.
+	SetLogger(log logger.Logger)
+	// CreateReplay saves the replay of a finished game.
+	CreateReplay(g *game.Game) (*Replay, error)
+	// GetReplayByID returns the replay of a game by the game's id.
+	GetReplayByID(id string) (*Replay, error)
+	// AddCommands adds the replay command group to the connection, see cmd.go
+	AddCommands(c conn.Conn)
+}
+
+type service struct {
+	repo Repository
+	log  logger.Logger
+}
+
+// NewService returns a new replay service.
+func NewService(repo Repository) (Service, error) {
+	if repo == nil || !repo.IsValid() {
+		return nil, fmt.Errorf("!repo.IsValid()")
+	}
+
+	return &service{
+		repo: repo,
+		log:  logger.NullLogger(),
+	}, nil
+}
+
+// SetLogger sets the logger for the service
+func (s *service) SetLogger(log logger.Logger) {
+	if log != nil {
+		s.log = log
+		log.Info("Set Logger")
+	}
+}
+
+// CreateReplay saves the replay of a finished game.
+func (s *service) CreateReplay(g *game.Game) (*Replay, error) {
+	r, err := NewReplay(g)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Create(r)
+	if err != nil {
+		return nil, fmt.Errorf("repo.Create: %w", err)
+	}
+
+	s.log.Debug("s.CreateReplay: %s", r.ID)
+	return r, nil
+}
+
+// GetReplayByID returns the replay of a game by the game's id.
+func (s *service) GetReplayByID(id string) (*Replay, error) {
+	r, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetByID: %w", err)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,227 @@
+package replay
+
+import (
+	"sync"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+const (
+	// MinSpeed is the slowest speed of a stream, a quarter of the real time.
+	MinSpeed = 0.25
+	// MaxSpeed is the fastest speed of a stream.
+	MaxSpeed = 64
+	// MaxDelay is the longest wait between two events, so a stream doesn't stall on a player that took their time.
+	MaxDelay = time.Second * 5
+)
+
+// Stream sends the events of a replay to a connection, one at a time. Events are sent as replay.event,
+// stepping back sends replay.seek with every event up to the new position so the client can rebuild the game.
+type Stream struct {
+	c      conn.Conn
+	replay *Replay
+
+	// the amount of events that have been sent
+	cursor  int
+	speed   float64
+	playing bool
+
+	wake chan bool
+	done chan bool
+	mtx  sync.Mutex
+}
+
+// NewStream sends the replay to c at the speed, it starts playing right away.
+func NewStream(c conn.Conn, r *Replay, speed float64) (*Stream, error) {
+	if speed < MinSpeed || speed > MaxSpeed {
+		return nil, ErrSpeed
+	}
+
+	s := &Stream{
+		c:       c,
+		replay:  r,
+		speed:   speed,
+		playing: true,
+		wake:    make(chan bool, 1),
+		done:    make(chan bool),
+	}
+
+	err := c.WriteMessage(conn.MessageSend{
+		Group: "replay",
+		Name:  "get",
+		Body:  r.header(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.run()
+
+	return s, nil
+}
+
+// run sends the next event whenever its time comes, until the stream gets stopped.
+func (s *Stream) run() {
+	for {
+		var timer *time.Timer
+		var next <-chan time.Time
+
+		s.mtx.Lock()
+		if s.playing && s.cursor < len(s.replay.Events) {
+			timer = time.NewTimer(s.delay())
+			next = timer.C
+		}
+		s.mtx.Unlock()
+
+		select {
+		case <-s.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-s.wake:
+			if timer != nil {
+				timer.Stop()
+			}
+		case <-next:
+			s.step()
+		}
+	}
+}
+
+// delay returns the time to wait before sending the next event. s.mtx needs to be locked before calling it.
+func (s *Stream) delay() time.Duration {
+	if s.cursor == 0 {
+		return 0
+	}
+
+	events := s.replay.Events
+	gap := events[s.cursor].Time.Sub(events[s.cursor-1].Time)
+
+	delay := time.Duration(float64(gap) / s.speed)
+	if delay > MaxDelay {
+		delay = MaxDelay
+	}
+
+	return delay
+}
+
+// notify wakes up run, so it picks up the changes.
+func (s *Stream) notify() {
+	select {
+	case s.wake <- true:
+	default:
+	}
+}
+
+// step sends the next event, the stream gets paused once every event has been sent.
+func (s *Stream) step() error {
+	s.mtx.Lock()
+	if s.cursor >= len(s.replay.Events) {
+		s.mtx.Unlock()
+		return ErrStep
+	}
+
+	event := s.replay.Events[s.cursor]
+	s.cursor++
+
+	last := s.cursor == len(s.replay.Events)
+	if last {
+		s.playing = false
+	}
+	s.mtx.Unlock()
+
+	err := s.c.WriteMessage(conn.MessageSend{
+		Group: "replay",
+		Name:  "event",
+		Body:  event,
+	})
+	if err != nil {
+		return err
+	}
+
+	if last {
+		return s.c.WriteMessage(conn.MessageSend{
+			Group: "replay",
+			Name:  "end",
+		})
+	}
+
+	return nil
+}
+
+// Play resumes the stream.
+func (s *Stream) Play() {
+	s.mtx.Lock()
+	s.playing = true
+	s.mtx.Unlock()
+
+	s.notify()
+}
+
+// Pause pauses the stream.
+func (s *Stream) Pause() {
+	s.mtx.Lock()
+	s.playing = false
+	s.mtx.Unlock()
+
+	s.notify()
+}
+
+// SetSpeed changes the speed of the stream, the speed is relative to the real time of the game.
+func (s *Stream) SetSpeed(speed float64) error {
+	if speed < MinSpeed || speed > MaxSpeed {
+		return ErrSpeed
+	}
+
+	s.mtx.Lock()
+	s.speed = speed
+	s.mtx.Unlock()
+
+	s.notify()
+	return nil
+}
+
+// Forward pauses the stream and sends the next event.
+func (s *Stream) Forward() error {
+	s.Pause()
+	return s.step()
+}
+
+// Back pauses the stream and moves it one event back, the client gets every event up to the new position through replay.seek.
+func (s *Stream) Back() error {
+	s.mtx.Lock()
+	if s.cursor == 0 {
+		s.mtx.Unlock()
+		return ErrStep
+	}
+
+	s.playing = false
+	s.cursor--
+
+	events := make([]game.Event, s.cursor)
+	copy(events, s.replay.Events)
+	s.mtx.Unlock()
+
+	s.notify()
+
+	return s.c.WriteMessage(conn.MessageSend{
+		Group: "replay",
+		Name:  "seek",
+		Body:  events,
+	})
+}
+
+// Stop stops the stream for good.
+func (s *Stream) Stop() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+}
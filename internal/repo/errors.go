@@ -12,3 +12,8 @@ var ErrClientInvalid = errors.New("Client has not been initialized")
 var ErrLobbyExists = errors.New("Lobby already exists")
 var ErrLobby404 = errors.New("Lobby does not exist")
 var ErrLobbyInvalid = errors.New("Lobby has not been initialized")
+
+// Replay
+var ErrReplayExists = errors.New("Replay already exists")
+var ErrReplay404 = errors.New("Replay does not exist")
+var ErrReplayInvalid = errors.New("Replay has not been initialized")
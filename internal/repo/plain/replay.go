@@ -0,0 +1,61 @@
+package plain
+
+import (
+	"sync"
+
+	"github.com/toms1441/resistance-server/internal/replay"
+	"github.com/toms1441/resistance-server/internal/repo"
+)
+
+type replayRepository struct {
+	db map[string]*replay.Replay
+	mx sync.Mutex
+}
+
+func NewReplayRepository() replay.Repository {
+	return &replayRepository{
+		db: map[string]*replay.Replay{},
+	}
+}
+
+func (r *replayRepository) Create(rp *replay.Replay) error {
+	if r.db == nil {
+		return repo.ErrReplayInvalid
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	_, ok := r.db[rp.ID]
+	if ok {
+		return repo.ErrReplayExists
+	}
+
+	r.db[rp.ID] = rp
+
+	return nil
+}
+
+func (r *replayRepository) GetByID(id string) (*replay.Replay, error) {
+	if r.db == nil {
+		return nil, repo.ErrReplayInvalid
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	rp, ok := r.db[id]
+	if !ok {
+		return nil, repo.ErrReplay404
+	}
+
+	return rp, nil
+}
+
+func (r *replayRepository) IsValid() bool {
+	if r.db == nil {
+		return false
+	}
+
+	return true
+}
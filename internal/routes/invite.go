@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/toms1441/resistance-server/internal/lobby"
+	"github.com/toms1441/resistance-server/internal/repo"
+)
+
+// NewInviteRoute returns a handler for GET /invite/:token, it responds with the lobby that the invite token belongs to.
+// It's what sharable join links resolve through, the client then joins with lobby.join and the same token.
+func NewInviteRoute(lserv lobby.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l, err := lserv.GetLobbyByInvite(c.Param("token"))
+		if err != nil {
+			if errors.Is(err, repo.ErrLobby404) {
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, l.Snapshot())
+	}
+}
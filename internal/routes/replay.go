@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/toms1441/resistance-server/internal/replay"
+	"github.com/toms1441/resistance-server/internal/repo"
+)
+
+// NewReplayRoute returns a handler for GET /games/:id/replay, it responds with the replay of a finished game.
+// Games that are still running don't have a replay, so they're not found.
+func NewReplayRoute(rserv replay.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r, err := rserv.GetReplayByID(c.Param("id"))
+		if err != nil {
+			if errors.Is(err, repo.ErrReplay404) {
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, r)
+	}
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -14,12 +15,15 @@ import (
 	"github.com/toms1441/resistance-server/internal/discord"
 	"github.com/toms1441/resistance-server/internal/lobby"
 	"github.com/toms1441/resistance-server/internal/logger"
+	"github.com/toms1441/resistance-server/internal/replay"
 )
 
 // WebsocketConfig is a data structure containing config to use in NewWebsocketRoute.
 type WebsocketConfig struct {
 	LobbyService  lobby.Service
 	ClientService client.Service
+	// ReplayService is optional, without it clients can't watch replays
+	ReplayService replay.Service
 	Log           logger.Logger
 	GetUser       func(c *gin.Context) (discord.User, error)
 }
@@ -35,11 +39,19 @@ type context struct {
 func (c context) marshalLobbies() ([]byte, error) {
 	lserv := c.LobbyService
 
-	lls, err := lserv.GetAllLobbies()
+	all, err := lserv.GetAllLobbies()
 	if err != nil {
 		return nil, err
 	}
 
+	// private lobbies are only joined through their invite, so they're not listed
+	lls := []lobby.Snapshot{}
+	for _, l := range all {
+		if s := l.Snapshot(); !s.Private {
+			lls = append(lls, s)
+		}
+	}
+
 	ms := conn.MessageSend{
 		Group: "lobbies",
 		Name:  "get",
@@ -96,7 +108,40 @@ func (c context) leaveLobby() {
 	}
 }
 
-// addCommands adds: "lobby_create", "lobby_join", "lobbies_get"
+// access is the body of lobby.join and lobby.spectate. Private lobbies need the invite or the password, the invite alone is enough to find the lobby.
+type access struct {
+	ID       string
+	Invite   string
+	Password string
+}
+
+// getLobby returns the lobby that the client wants to join, as long as they're allowed to.
+func (c context) getLobby(a access) (*lobby.Lobby, error) {
+	lserv := c.LobbyService
+
+	var l *lobby.Lobby
+	var err error
+	if len(a.ID) == 0 && len(a.Invite) > 0 {
+		l, err = lserv.GetLobbyByInvite(a.Invite)
+		if err != nil {
+			return nil, fmt.Errorf("lserv.GetLobbyByInvite: %w", err)
+		}
+	} else {
+		l, err = lserv.GetLobbyByID(a.ID)
+		if err != nil {
+			return nil, fmt.Errorf("lserv.GetLobbyByID: %w", err)
+		}
+	}
+
+	err = l.Authorize(c.cl.GetClient().ID, a.Invite, a.Password)
+	if err != nil {
+		return nil, fmt.Errorf("l.Authorize: %w", err)
+	}
+
+	return l, nil
+}
+
+// addCommands adds: "lobby_create", "lobby_join", "lobby_spectate", "lobbies_get"
 func (c context) addCommands() {
 	lserv, cserv := c.LobbyService, c.ClientService
 
@@ -116,9 +161,16 @@ func (c context) addCommands() {
 				return fmt.Errorf("lserv.CreateLobby: %w", err)
 			}
 
+			// the password isn't a part of the lobby's json
+			access := struct {
+				Password string
+			}{}
+			json.Unmarshal(bytes, &access)
+			l.SetPassword(access.Password)
+
 			// goroutine to indicate any update in the amount of players
 			// in-case there is, we update every client.
-			insert, remove := l.SubscribeInsert(), l.SubscribeRemove()
+			insert, remove, update := l.SubscribeInsert(), l.SubscribeRemove(), l.SubscribeUpdate()
 			go func(cserv client.Service, lserv lobby.Service, l *lobby.Lobby) {
 				for {
 					select {
@@ -126,6 +178,8 @@ func (c context) addCommands() {
 						c.sendLobbies()
 					case <-remove:
 						c.sendLobbies()
+					case <-update:
+						c.sendLobbies()
 					}
 				}
 			}(cserv, lserv, l)
@@ -142,27 +196,67 @@ func (c context) addCommands() {
 		"join": func(log logger.Logger, bytes []byte) error {
 			c.leaveLobby()
 
-			l := new(lobby.Lobby)
-			err := json.Unmarshal(bytes, l)
+			join := access{}
+			err := json.Unmarshal(bytes, &join)
 			if err != nil {
 				return fmt.Errorf("json.Unmarshal: %w", err)
 			}
 
-			l, err = lserv.GetLobbyByID(l.ID)
+			l, err := c.getLobby(join)
 			if err != nil {
-				return fmt.Errorf("json.Unmarshal: %w", err)
+				return err
 			}
 
 			err = l.Join(c.cl)
+			if errors.Is(err, lobby.ErrLobbyFull) {
+				// the client gets the seat once it frees up
+				err = l.Wait(c.cl)
+				if err != nil {
+					return fmt.Errorf("l.Wait: %w", err)
+				}
+
+				return nil
+			}
+
 			if err != nil {
 				return fmt.Errorf("l.Join: %v", err)
 			}
 
 			return nil
 		},
+
+		// This command joins a lobby as a spectator, casters see every role after the lobby's caster delay
+		"spectate": func(log logger.Logger, bytes []byte) error {
+			c.leaveLobby()
+
+			spectate := struct {
+				access
+				Caster bool
+			}{}
+			err := json.Unmarshal(bytes, &spectate)
+			if err != nil {
+				return fmt.Errorf("json.Unmarshal: %w", err)
+			}
+
+			l, err := c.getLobby(spectate.access)
+			if err != nil {
+				return err
+			}
+
+			err = l.Spectate(c.cl, spectate.Caster)
+			if err != nil {
+				return fmt.Errorf("l.Spectate: %w", err)
+			}
+
+			return nil
+		},
 	}
 
 	c.cl.AddCommand("lobby", msgstrct)
+	if c.ReplayService != nil {
+		c.ReplayService.AddCommands(c.cl)
+	}
+
 	c.cl.AddCommand("lobbies", conn.MessageStruct{
 		"get": func(log logger.Logger, _ []byte) error {
 			body, err := c.marshalLobbies()
@@ -254,6 +348,21 @@ func NewWebsocketRoute(config WebsocketConfig) gin.HandlerFunc {
 
 		c.cl.WriteBytes(body)
 
+		// the client might be reconnecting to a lobby, in which case they get their seat back
+		ls, err := lserv.GetAllLobbies()
+		if err == nil {
+			for _, l := range ls {
+				if l.GetClientIndex(cl.ID) == -1 {
+					continue
+				}
+
+				err = l.Rebind(c.cl)
+				if err != nil {
+					log.Debug("l.Rebind(%s): %v", cl.ID, err)
+				}
+			}
+		}
+
 		if debuglobby {
 			ls, err := lserv.GetAllLobbies()
 			if err != nil {
@@ -0,0 +1,97 @@
+package simulate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+// ParsePlayers parses a comma separated list of player amounts, i.e "5,6,7".
+func ParsePlayers(str string) ([]int, error) {
+	players := []int{}
+	for _, v := range split(str, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("strconv.Atoi: %w", err)
+		}
+
+		players = append(players, n)
+	}
+
+	return players, nil
+}
+
+// ParseTypes parses a comma separated list of game types by their name, case insensitive. i.e "basic,avalon".
+func ParseTypes(str string) ([]game.Type, error) {
+	types := []game.Type{}
+	for _, v := range split(str, ",") {
+		t, ok := parseType(v)
+		if !ok {
+			return nil, fmt.Errorf("unknown type: %s", v)
+		}
+
+		types = append(types, t)
+	}
+
+	return types, nil
+}
+
+func parseType(str string) (game.Type, bool) {
+	for t := game.TypeBasic; t.String() != ""; t++ {
+		if strings.EqualFold(t.String(), str) {
+			return t, true
+		}
+	}
+
+	return 0, false
+}
+
+// ParseOptions parses a comma separated list of option combinations, the options in each combination are separated by a plus sign.
+// i.e "none,percival+morgana,assassin"
+func ParseOptions(str string) ([]game.Option, error) {
+	options := []game.Option{}
+	for _, v := range split(str, ",") {
+		o := game.OptionNone
+		for _, name := range split(v, "+") {
+			option, ok := parseOption(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown option: %s", name)
+			}
+
+			o = o.Add(option)
+		}
+
+		options = append(options, o)
+	}
+
+	return options, nil
+}
+
+func parseOption(str string) (game.Option, bool) {
+	if strings.EqualFold(game.OptionNone.String(), str) {
+		return game.OptionNone, true
+	}
+
+	for o := game.OptionPercival; o != 0; o <<= 1 {
+		if strings.EqualFold(o.String(), str) {
+			return o, true
+		}
+	}
+
+	return 0, false
+}
+
+// split splits str by sep, and drops the empty parts.
+func split(str, sep string) []string {
+	strs := []string{}
+	for _, v := range strings.Split(str, sep) {
+		v = strings.TrimSpace(v)
+		if len(v) > 0 {
+			strs = append(strs, v)
+		}
+	}
+
+	return strs
+}
@@ -0,0 +1,100 @@
+package simulate
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	// FormatJSON writes the report as indented json.
+	FormatJSON = "json"
+	// FormatCSV writes the report as csv, one row per role in each setup.
+	FormatCSV = "csv"
+)
+
+// Write writes the report to w in the format, either FormatJSON or FormatCSV.
+func (r Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		return r.WriteJSON(w)
+	case FormatCSV:
+		return r.WriteCSV(w)
+	}
+
+	return fmt.Errorf("unknown format: %s", format)
+}
+
+// WriteJSON writes the report to w as indented json.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(r)
+	if err != nil {
+		return fmt.Errorf("enc.Encode: %w", err)
+	}
+
+	return nil
+}
+
+// csvHeader is the first row of the csv report.
+var csvHeader = []string{"players", "type", "option", "games", "resistance", "spies", "winrate", "role", "role_games", "role_wins", "role_winrate", "error"}
+
+// WriteCSV writes the report to w as csv. Each row is a role in a setup, so the setup columns repeat for every role.
+// A setup that couldn't be played has a single row with the error.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(csvHeader)
+	if err != nil {
+		return fmt.Errorf("cw.Write: %w", err)
+	}
+
+	for _, v := range r.Results {
+		setup := []string{
+			strconv.Itoa(v.Players),
+			v.Type.String(),
+			v.Option.String(),
+			strconv.Itoa(v.Games),
+			strconv.Itoa(v.Resistance),
+			strconv.Itoa(v.Spies),
+			formatRate(v.WinRate),
+		}
+
+		if len(v.Roles) == 0 {
+			err = cw.Write(append(setup, "", "", "", "", v.Error))
+			if err != nil {
+				return fmt.Errorf("cw.Write: %w", err)
+			}
+
+			continue
+		}
+
+		for _, name := range v.roleNames() {
+			role := v.Roles[name]
+
+			row := append(append([]string{}, setup...),
+				name,
+				strconv.Itoa(role.Games),
+				strconv.Itoa(role.Wins),
+				formatRate(role.WinRate),
+				v.Error,
+			)
+
+			err = cw.Write(row)
+			if err != nil {
+				return fmt.Errorf("cw.Write: %w", err)
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatRate(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
@@ -0,0 +1,283 @@
+package simulate
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+// Config is the configuration of a simulation. Every combination of Players, Types and Options is a setup, and each setup gets played Games times.
+type Config struct {
+	// the amount of games per setup
+	Games int
+	// the amount of players, between 5 and 10
+	Players []int
+	Types   []game.Type
+	Options []game.Option
+	// the name of the strategy that every bot plays with, see bot.Strategies
+	Strategy string
+	// the ruleset that the games use, nil means game.DefaultRuleset
+	Ruleset game.Ruleset
+	// the amount of games that run at the same time, zero means runtime.NumCPU
+	Workers int
+}
+
+var (
+	// ErrGames occurs when the amount of games is zero or less
+	ErrGames = errors.New("simulation games must be more than zero")
+	// ErrSetups occurs when there are no players, types or options
+	ErrSetups = errors.New("simulation needs at least one amount of players, type and option")
+	// ErrStrategy occurs when the strategy isn't in bot.Strategies
+	ErrStrategy = errors.New("simulation strategy does not exist")
+)
+
+// Validate returns an error if the config can't be simulated.
+func (c Config) Validate() error {
+	if c.Games <= 0 {
+		return ErrGames
+	}
+
+	if len(c.Players) == 0 || len(c.Types) == 0 || len(c.Options) == 0 {
+		return ErrSetups
+	}
+
+	if _, ok := bot.Strategies[c.Strategy]; !ok {
+		return ErrStrategy
+	}
+
+	return nil
+}
+
+// Timers are the timers of a simulated game. The bots play game.choose, game.vote and game.decide right away,
+// every other phase times out as soon as it starts, i.e the assassin never assassinates and the lady of the lake is never used.
+var Timers = game.Timers{
+	Choose:      time.Second * 10,
+	Vote:        time.Second * 10,
+	Decide:      time.Second * 10,
+	Deal:        time.Millisecond,
+	React:       time.Millisecond,
+	Lady:        time.Millisecond,
+	Investigate: time.Millisecond,
+	Assassinate: time.Millisecond,
+	Hunt:        time.Millisecond,
+}
+
+// Setup is a single combination of the amount of players, the type and the options.
+type Setup struct {
+	Players int         `json:"players"`
+	Type    game.Type   `json:"type"`
+	Option  game.Option `json:"option"`
+}
+
+// Result is the outcome of every game played with a setup.
+type Result struct {
+	Setup
+	// the amount of games that were played
+	Games int `json:"games"`
+	// the amount of games that each team won
+	Resistance int `json:"resistance"`
+	Spies      int `json:"spies"`
+	// the rate at which the resistance won, between 0 and 1
+	WinRate float64 `json:"winrate"`
+	// the win rate of each role, by the role's name
+	Roles map[string]*Role `json:"roles"`
+	// the reason that the setup couldn't be played, i.e too many spy roles for the amount of players
+	Error string `json:"error,omitempty"`
+}
+
+// Role is the outcome of every player that got a role.
+type Role struct {
+	// the amount of players that got the role
+	Games int `json:"games"`
+	// the amount of them that won
+	Wins int `json:"wins"`
+	// the rate at which the role won, between 0 and 1
+	WinRate float64 `json:"winrate"`
+}
+
+// Report is the outcome of a simulation, ordered by setup.
+type Report struct {
+	Results []*Result `json:"results"`
+}
+
+// Run plays every setup in the config, and returns the report once every game is finished.
+func Run(c Config) (Report, error) {
+	if err := c.Validate(); err != nil {
+		return Report{}, err
+	}
+
+	if c.Ruleset == nil {
+		c.Ruleset = game.DefaultRuleset
+	}
+
+	if c.Workers <= 0 {
+		c.Workers = runtime.NumCPU()
+	}
+
+	report := Report{}
+	for _, players := range c.Players {
+		for _, t := range c.Types {
+			for _, o := range c.Options {
+				report.Results = append(report.Results, &Result{
+					Setup: Setup{
+						Players: players,
+						Type:    t,
+						Option:  o,
+					},
+					Roles: map[string]*Role{},
+				})
+			}
+		}
+	}
+
+	jobs := make(chan *Result)
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < c.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for r := range jobs {
+				o, err := play(c, r.Setup)
+
+				mtx.Lock()
+				if err != nil {
+					r.Error = err.Error()
+				} else {
+					r.add(o)
+				}
+				mtx.Unlock()
+			}
+		}()
+	}
+
+	for _, r := range report.Results {
+		for i := 0; i < c.Games; i++ {
+			mtx.Lock()
+			failed := len(r.Error) > 0
+			mtx.Unlock()
+
+			// the setup can't be played, no need to try again
+			if failed {
+				break
+			}
+
+			jobs <- r
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	for _, r := range report.Results {
+		r.WinRate = rate(r.Resistance, r.Games)
+		for _, role := range r.Roles {
+			role.WinRate = rate(role.Wins, role.Games)
+		}
+	}
+
+	return report, nil
+}
+
+// outcome is the outcome of a single game.
+type outcome struct {
+	status game.Status
+	// players that won the game
+	// by id
+	winners map[string]bool
+	// the final role of each player
+	// by id
+	roles map[string]game.PlayerType
+}
+
+// add counts a single game in the result.
+func (r *Result) add(o outcome) {
+	r.Games++
+
+	switch o.status {
+	case game.StatusWon:
+		r.Resistance++
+	case game.StatusLost:
+		r.Spies++
+	}
+
+	for id, pt := range o.roles {
+		role, ok := r.Roles[pt.String()]
+		if !ok {
+			role = &Role{}
+			r.Roles[pt.String()] = role
+		}
+
+		role.Games++
+		if o.winners[id] {
+			role.Wins++
+		}
+	}
+}
+
+// rate returns n out of games, between 0 and 1.
+func rate(n, games int) float64 {
+	if games == 0 {
+		return 0
+	}
+
+	return float64(n) / float64(games)
+}
+
+// roleNames returns the names of the roles in the result, sorted.
+func (r *Result) roleNames() []string {
+	names := []string{}
+	for name := range r.Roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// play plays a single game with bots.
+func play(c Config, s Setup) (outcome, error) {
+	strategy := bot.Strategies[c.Strategy]
+
+	conns := map[string]conn.Conn{}
+	for i := 0; i < s.Players; i++ {
+		b := bot.NewBot(strategy)
+		defer b.Destroy()
+
+		conns[b.GetClient().ID] = b
+	}
+
+	g, err := game.NewGameWithRuleset(conns, s.Type.Common(), s.Option, c.Ruleset)
+	if err != nil {
+		return outcome{}, fmt.Errorf("game.NewGameWithRuleset: %w", err)
+	}
+	g.SetTimers(Timers)
+
+	status := make(chan game.Status)
+	go g.Run(status)
+
+	o := outcome{
+		status:  <-status,
+		winners: map[string]bool{},
+		roles:   map[string]game.PlayerType{},
+	}
+
+	for _, id := range g.GetWinners() {
+		o.winners[id] = true
+	}
+
+	for id, p := range g.Players {
+		o.roles[id] = p.Type
+	}
+
+	return o, nil
+}
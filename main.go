@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/url"
+	"os"
 	"time"
 
 	"github.com/fatih/color"
@@ -15,6 +16,7 @@ import (
 	"github.com/toms1441/resistance-server/internal/discord"
 	"github.com/toms1441/resistance-server/internal/lobby"
 	"github.com/toms1441/resistance-server/internal/logger"
+	"github.com/toms1441/resistance-server/internal/replay"
 	"github.com/toms1441/resistance-server/internal/repo/plain"
 	"github.com/toms1441/resistance-server/internal/routes"
 	"golang.org/x/oauth2"
@@ -29,6 +31,16 @@ func main() {
 
 	main := logger.NewLogger(lc)
 
+	// the simulate subcommand plays games with bots, without running the web server
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		err := runSimulate(os.Args[2:])
+		if err != nil {
+			main.Fatal("runSimulate: %v", err)
+		}
+
+		return
+	}
+
 	c, err := config.NewConfig()
 	if err != nil {
 		main.Fatal("config.NewConfig: %v", err)
@@ -92,6 +104,26 @@ func main() {
 		}, nil
 	}
 
+	// replay service init
+	// the lobbies save finished games to it
+	var rserv replay.Service
+	{
+		lc = logger.DefaultConfig
+		lc.PAttr = color.New(color.FgMagenta, color.Italic)
+		lc.Prefix = "replay"
+		lc.Debug = true
+
+		var err error
+		rserv, err = replay.NewService(plain.NewReplayRepository())
+		if err != nil {
+			main.Fatal("an error occurred with creating the replay service: %v", err)
+		}
+
+		rserv.SetLogger(logger.NewLogger(lc))
+
+		r.GET("/games/:id/replay", routes.NewReplayRoute(rserv))
+	}
+
 	// lobby service init
 	// make the lobby service kinda global
 	// so we can use it in websocket route
@@ -112,6 +144,9 @@ func main() {
 		}
 
 		lserv.SetLogger(llog)
+		lserv.SetReplayService(rserv)
+
+		r.GET("/invite/:token", routes.NewInviteRoute(lserv))
 	}
 
 	// client service init
@@ -146,6 +181,7 @@ func main() {
 			routes.WebsocketConfig{
 				LobbyService:  lserv,
 				ClientService: cserv,
+				ReplayService: rserv,
 				Log:           logger.NewLogger(lc),
 				GetUser:       getuser,
 			},
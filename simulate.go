@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/simulate"
+)
+
+// runSimulate is the simulate subcommand, it plays games with bots and writes win rates by setup and role.
+//
+//	resistance-server simulate -games 1000 -players 5,6,7 -types avalon -options none,percival+morgana -format csv
+func runSimulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+
+	games := fs.Int("games", 1000, "amount of games per setup")
+	players := fs.String("players", "5,6,7,8,9,10", "comma separated amounts of players")
+	types := fs.String("types", "basic", "comma separated game types, i.e basic,avalon")
+	options := fs.String("options", "none", "comma separated option combinations, options are joined by +. i.e none,percival+morgana")
+	strategy := fs.String("strategy", bot.StrategyCautious, "strategy of the bots: random, cautious or aggressive")
+	ruleset := fs.String("ruleset", "", "path to a ruleset in json, the official ruleset is used if empty")
+	workers := fs.Int("workers", 0, "amount of games that run at the same time, defaults to the amount of cpus")
+	format := fs.String("format", simulate.FormatJSON, "output format: json or csv")
+	out := fs.String("out", "", "output file, stdout if empty")
+
+	err := fs.Parse(args)
+	if err == flag.ErrHelp {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	c := simulate.Config{
+		Games:    *games,
+		Strategy: *strategy,
+		Workers:  *workers,
+	}
+
+	c.Players, err = simulate.ParsePlayers(*players)
+	if err != nil {
+		return fmt.Errorf("simulate.ParsePlayers: %w", err)
+	}
+
+	c.Types, err = simulate.ParseTypes(*types)
+	if err != nil {
+		return fmt.Errorf("simulate.ParseTypes: %w", err)
+	}
+
+	c.Options, err = simulate.ParseOptions(*options)
+	if err != nil {
+		return fmt.Errorf("simulate.ParseOptions: %w", err)
+	}
+
+	if len(*ruleset) > 0 {
+		body, err := os.ReadFile(*ruleset)
+		if err != nil {
+			return fmt.Errorf("os.ReadFile: %w", err)
+		}
+
+		c.Ruleset, err = game.ParseRuleset(body)
+		if err != nil {
+			return fmt.Errorf("game.ParseRuleset: %w", err)
+		}
+	}
+
+	if *format != simulate.FormatJSON && *format != simulate.FormatCSV {
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	report, err := simulate.Run(c)
+	if err != nil {
+		return fmt.Errorf("simulate.Run: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if len(*out) > 0 {
+		file, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("os.Create: %w", err)
+		}
+		defer file.Close()
+
+		w = file
+	}
+
+	return report.Write(w, *format)
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+func TestBotGame(t *testing.T) {
+	names := []string{bot.StrategyRandom, bot.StrategyCautious, bot.StrategyAggressive, bot.StrategyCautious, bot.StrategyAggressive}
+
+	mapconn := map[string]conn.Conn{}
+	for _, v := range names {
+		b := bot.NewBot(bot.Strategies[v])
+		defer b.Destroy()
+
+		mapconn[b.GetClient().ID] = b
+	}
+
+	g, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionNone)
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+
+	// the timers are longer than the test, so the game only finishes if the bots play every phase
+	g.SetTimers(game.TimerPresets[game.TimersCasual])
+
+	done := make(chan game.Status)
+	go g.Run(done)
+
+	select {
+	case have := <-done:
+		if have != game.StatusWon && have != game.StatusLost {
+			t.Fatalf("want: '%s' or '%s', have: '%s'", game.StatusWon.String(), game.StatusLost.String(), have.String())
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out, the bots didn't play")
+	}
+}
+
+func TestBotDestroy(t *testing.T) {
+	b := bot.NewBot(bot.Random{})
+	done := b.GetDone()
+
+	go b.Destroy()
+
+	select {
+	case <-done:
+	case <-time.After(time.Millisecond * 100):
+		t.Fatal("b.GetDone was not set")
+	}
+
+	// writing to a destroyed bot does nothing
+	b.WriteBytes([]byte("{}"))
+}
@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+func newState(id string, spies ...string) bot.State {
+	s := bot.State{
+		ID:      id,
+		Players: map[string]game.PlayerType{},
+		Seats:   []string{"a", "b", "c", "d", "e"},
+		Rounds:  game.DefaultRuleset[5].Rounds(),
+	}
+
+	for _, v := range s.Seats {
+		s.Players[v] = game.PlayerTypeResistance
+	}
+
+	for _, v := range spies {
+		s.Players[v] = game.PlayerTypeSpy
+	}
+
+	return s
+}
+
+func TestStrategyChoose(t *testing.T) {
+	for name, strategy := range bot.Strategies {
+		for _, s := range []bot.State{newState("a"), newState("a", "a", "b")} {
+			for ri := 0; ri < 5; ri++ {
+				s.Results = make([]game.Round, ri)
+
+				team := strategy.Choose(s)
+				if len(team) != s.Size() {
+					t.Fatalf("%s, round %d - want: %d, have: %d", name, ri, s.Size(), len(team))
+				}
+
+				seen := map[string]bool{}
+				for _, id := range team {
+					if seen[id] {
+						t.Fatalf("%s, round %d - %s is in the team twice", name, ri, id)
+					}
+					seen[id] = true
+				}
+			}
+		}
+	}
+}
+
+func TestStrategyCautious(t *testing.T) {
+	strategy := bot.Cautious{}
+
+	// merlin knows that e is a spy
+	s := newState("a", "e")
+	s.Players["a"] = game.PlayerTypeMerlin
+
+	for _, id := range strategy.Choose(s) {
+		if id == "e" {
+			t.Fatal("the resistance chose a known spy")
+		}
+	}
+
+	if strategy.Vote(s, []string{"a", "e"}) {
+		t.Fatal("the resistance accepted a team with a known spy")
+	}
+
+	// declining the last proposal loses the round
+	s.Proposal = int(s.Rounds[0].Proposals) - 1
+	if !strategy.Vote(s, []string{"a", "e"}) {
+		t.Fatal("the resistance declined the last proposal")
+	}
+
+	// a spy alone on the team fails the mission, two spies leave it to each other
+	spy := newState("a", "a", "b")
+	if strategy.Decide(spy, []string{"a", "c"}) {
+		t.Fatal("a spy alone on the team did not fail the mission")
+	}
+
+	if !strategy.Decide(spy, []string{"a", "b"}) {
+		t.Fatal("a spy failed the mission with another spy on the team")
+	}
+}
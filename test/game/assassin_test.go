@@ -0,0 +1,69 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+func TestGameAssassination(t *testing.T) {
+	merlin := func(g *game.Game) string {
+		for k, v := range g.Players {
+			if v.Type == game.PlayerTypeMerlin {
+				return k
+			}
+		}
+
+		return ""
+	}
+
+	resistance := func(g *game.Game) string {
+		for k, v := range g.Players {
+			if v.Type == game.PlayerTypeResistance {
+				return k
+			}
+		}
+
+		return ""
+	}
+
+	// assassinate returns a game.assassinate command that picks target(g) whenever the player is an assassin
+	assassinate := func(target func(g *game.Game) string) func(lp *loopParameter) conn.MessageStruct {
+		return func(lp *loopParameter) conn.MessageStruct {
+			return conn.MessageStruct{
+				"assassinate": func(log logger.Logger, body []byte) error {
+					assassination := game.Assassination{}
+					if err := json.Unmarshal(body, &assassination); err != nil {
+						t.Errorf("json.Unmarshal: %v", err)
+						return err
+					}
+
+					for _, v := range assassination.Assassins {
+						if v == lp.player.GetClient().ID {
+							lp.vsn.WriteMessage(conn.MessageSend{
+								Group: "game",
+								Name:  "assassinate",
+								Body:  target(lp.g),
+							})
+						}
+					}
+
+					return nil
+				},
+			}
+		}
+	}
+
+	_, have := testWinningGame(t, game.TypeAvalon, game.OptionNone, assassinate(merlin))
+	if have != game.StatusLost {
+		t.Fatalf("assassinated merlin - want: '%s', have: '%s'", game.StatusLost.String(), have.String())
+	}
+
+	_, have = testWinningGame(t, game.TypeAvalon, game.OptionAssassin, assassinate(resistance))
+	if have != game.StatusWon {
+		t.Fatalf("assassinated resistance - want: '%s', have: '%s'", game.StatusWon.String(), have.String())
+	}
+}
@@ -0,0 +1,79 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+func TestGameEnd(t *testing.T) {
+	g, err := game.NewGame(bots(t), game.TypeBasic.Common(), game.OptionNone)
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+	g.SetTimers(game.TimerPresets[game.TimersCasual])
+
+	spectator := newSpectatorConn("spectator")
+	if err := g.AddSpectator(spectator); err != nil {
+		t.Fatalf("g.AddSpectator: %v", err)
+	}
+
+	done := make(chan game.Status)
+	go g.Run(done)
+
+	var status game.Status
+	select {
+	case status = <-done:
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out")
+	}
+
+	ends := []game.End{}
+	spectator.mtx.Lock()
+	for _, v := range spectator.msgs {
+		if v.Group == "game" && v.Name == "end" {
+			end := game.End{}
+			err := json.Unmarshal(v.Body, &end)
+			if err != nil {
+				t.Fatalf("json.Unmarshal: %v", err)
+			}
+
+			ends = append(ends, end)
+		}
+	}
+	spectator.mtx.Unlock()
+
+	if len(ends) != 1 {
+		t.Fatalf("len(ends) - want: %d, have: %d", 1, len(ends))
+	}
+	end := ends[0]
+
+	if end.Status != status || len(end.Winners) != len(g.GetWinners()) {
+		t.Fatalf("end: %+v", end)
+	}
+
+	// even the spectator gets every role
+	for id, p := range g.Players {
+		if end.Players[id] != p.Type {
+			t.Fatalf("end.Players[%s] - want: %d, have: %d", id, p.Type, end.Players[id])
+		}
+	}
+
+	// without avalon or hunter, the game ends with the rounds
+	want := game.ReasonMissions
+	for _, r := range g.Rounds {
+		if r.Proposals > 0 && r.Missions[r.Proposals-1].IsDeclined() {
+			want = game.ReasonProposals
+		}
+	}
+
+	if end.Reason != want {
+		t.Fatalf("end.Reason - want: %v, have: %v", want, end.Reason)
+	}
+
+	if last := g.Log()[len(g.Log())-1]; last.Reason != end.Reason {
+		t.Fatalf("last event - want: %v, have: %v", end.Reason, last.Reason)
+	}
+}
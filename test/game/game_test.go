@@ -288,7 +288,10 @@ func TestGameRun(t *testing.T) {
 					"round": getRoundFunc(lp),
 
 					"get": func(log logger.Logger, body []byte) error {
+						lp.mtx.Lock()
 						json.Unmarshal(body, testgame)
+						lp.mtx.Unlock()
+
 						return nil
 					},
 				})
@@ -313,3 +316,86 @@ func TestGameRun(t *testing.T) {
 		}
 	}
 }
+
+func TestGameSendSpyRoles(t *testing.T) {
+	mapconn := map[string]conn.Conn{}
+	for _, v := range cn[:7] {
+		mapconn[v.GetClient().ID] = v
+	}
+
+	g, err := game.NewGame(mapconn, game.TypeAvalon.Common(), game.OptionMordred.Add(game.OptionOberon))
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+
+	// want returns the player type that p should see for v
+	want := func(p, v game.Player) game.PlayerType {
+		switch p.Type {
+		case game.PlayerTypeMerlin:
+			if v.Type == game.PlayerTypeMordred {
+				return game.PlayerTypeResistance
+			} else if v.Type == game.PlayerTypeOberon {
+				return game.PlayerTypeSpy
+			}
+		case game.PlayerTypeOberon:
+			return game.PlayerTypeResistance
+		case game.PlayerTypeSpy, game.PlayerTypeMordred:
+			if v.Type == game.PlayerTypeOberon || v.Type == game.PlayerTypeMerlin {
+				return game.PlayerTypeResistance
+			}
+		case game.PlayerTypeResistance:
+			return game.PlayerTypeResistance
+		}
+
+		return v.Type
+	}
+
+	var mtx sync.Mutex
+	num := 0
+	done := make(chan bool)
+
+	for i := 0; i < 7; i++ {
+		vsn := sn[i]
+		p := g.Players[cn[i].GetClient().ID]
+
+		vsn.AddCommand("game", conn.MessageStruct{
+			"get": func(log logger.Logger, bytes []byte) error {
+				thisgame := &TestGame{}
+				err := json.Unmarshal(bytes, thisgame)
+				if err != nil {
+					t.Errorf("json.Unmarshal: %v", err)
+				}
+
+				for k, v := range g.Players {
+					if k == p.GetClient().ID {
+						continue
+					}
+
+					have := game.PlayerType(thisgame.Players[k])
+					if want := want(p, v); want != have {
+						t.Errorf("player type: %d sees %d - want: %d, have: %d", p.Type, v.Type, want, have)
+					}
+				}
+
+				mtx.Lock()
+				defer mtx.Unlock()
+				num++
+				if num == len(g.Players) {
+					done <- true
+				}
+
+				return nil
+			},
+		})
+
+		defer vsn.RemoveCommandsByGroup("game")
+	}
+
+	g.Send()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 1):
+		t.Fatal("timed out")
+	}
+}
@@ -0,0 +1,186 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+func TestGameHunter(t *testing.T) {
+	for i := 5; i <= 10; i++ {
+		mapconn := map[string]conn.Conn{}
+		for _, v := range cn[:i] {
+			mapconn[v.GetClient().ID] = v
+		}
+
+		g, err := game.NewGame(mapconn, game.TypeHunter.Common(), game.OptionNone)
+		if err != nil {
+			t.Fatalf("game.NewGame: %v", err)
+		}
+
+		have := map[game.PlayerType]int{}
+		for _, v := range g.Players {
+			have[v.Type]++
+		}
+
+		for _, v := range []game.PlayerType{game.PlayerTypeChiefResistance, game.PlayerTypeHunterResistance, game.PlayerTypeChiefSpy, game.PlayerTypeHunterSpy} {
+			if have[v] != 1 {
+				t.Fatalf("player len: %d, player type: %d - want: 1, have: %d", i, v, have[v])
+			}
+		}
+	}
+
+	mapconn := map[string]conn.Conn{}
+	for _, v := range cn[:5] {
+		mapconn[v.GetClient().ID] = v
+	}
+
+	// oberon only exists in avalon
+	_, err := game.NewGame(mapconn, game.TypeHunter.Common(), game.OptionOberon)
+	if !errors.Is(err, game.ErrAvalonOption) {
+		t.Fatalf("want: %v, have: %v", game.ErrAvalonOption, err)
+	}
+
+	// a single spy can't be both the spy chief and the spy hunter
+	rules := game.DefaultRuleset[5]
+	rules.Spies = 1
+
+	_, err = game.NewGameWithRuleset(mapconn, game.TypeHunter.Common(), game.OptionNone, game.Ruleset{5: rules})
+	if err != game.ErrSpyRoles {
+		t.Fatalf("want: %v, have: %v", game.ErrSpyRoles, err)
+	}
+}
+
+func TestGameHunt(t *testing.T) {
+	var mtx sync.Mutex
+	results := []game.InvestigationResult{}
+
+	// find returns the first player with the type
+	find := func(g *game.Game, ptype game.PlayerType) string {
+		for k, v := range g.Players {
+			if v.Type == ptype {
+				return k
+			}
+		}
+
+		return ""
+	}
+
+	// hunt returns commands that investigate the first target, and accuse the player with the type in targets whenever the player is a hunter
+	// targets is by the type of the hunter
+	hunt := func(targets map[game.PlayerType]game.PlayerType) func(lp *loopParameter) conn.MessageStruct {
+		return func(lp *loopParameter) conn.MessageStruct {
+			id := lp.player.GetClient().ID
+
+			return conn.MessageStruct{
+				"investigate": func(log logger.Logger, body []byte) error {
+					investigation := game.Investigation{}
+					if err := json.Unmarshal(body, &investigation); err != nil {
+						t.Errorf("json.Unmarshal: %v", err)
+						return err
+					}
+
+					if investigation.Investigator == id && len(investigation.Targets) > 0 {
+						lp.vsn.WriteMessage(conn.MessageSend{
+							Group: "game",
+							Name:  "investigate",
+							Body:  investigation.Targets[0],
+						})
+					}
+
+					return nil
+				},
+				"investigation": func(log logger.Logger, body []byte) error {
+					result := game.InvestigationResult{}
+					if err := json.Unmarshal(body, &result); err != nil {
+						t.Errorf("json.Unmarshal: %v", err)
+						return err
+					}
+
+					mtx.Lock()
+					results = append(results, result)
+					mtx.Unlock()
+
+					return nil
+				},
+				"hunt": func(log logger.Logger, body []byte) error {
+					h := game.Hunt{}
+					if err := json.Unmarshal(body, &h); err != nil {
+						t.Errorf("json.Unmarshal: %v", err)
+						return err
+					}
+
+					if h.Hunter == id {
+						lp.vsn.WriteMessage(conn.MessageSend{
+							Group: "game",
+							Name:  "hunt",
+							Body:  find(lp.g, targets[lp.player.Type]),
+						})
+					}
+
+					return nil
+				},
+			}
+		}
+	}
+
+	g, have := testWinningGame(t, game.TypeHunter, game.OptionNone, hunt(map[game.PlayerType]game.PlayerType{
+		game.PlayerTypeHunterSpy:        game.PlayerTypeChiefResistance,
+		game.PlayerTypeHunterResistance: game.PlayerTypeHunterSpy,
+	}))
+	if have != game.StatusLost {
+		t.Fatalf("accused the chief - want: '%s', have: '%s'", game.StatusLost.String(), have.String())
+	}
+
+	// both hunters hunt, the spy hunter first
+	if len(g.Hunts) != 2 {
+		t.Fatalf("len(g.Hunts) - want: %d, have: %d", 2, len(g.Hunts))
+	}
+
+	for k, v := range []game.PlayerType{game.PlayerTypeHunterSpy, game.PlayerTypeHunterResistance} {
+		if g.Players[g.Hunts[k].Hunter].Type != v {
+			t.Fatalf("hunter - want: %d, have: %d", v, g.Players[g.Hunts[k].Hunter].Type)
+		}
+	}
+
+	// the game ends after the 3rd round, so the investigation only happens after the 2nd round
+	if len(g.Investigations) != 1 {
+		t.Fatalf("len(g.Investigations) - want: %d, have: %d", 1, len(g.Investigations))
+	}
+
+	time.Sleep(time.Millisecond * 10)
+
+	mtx.Lock()
+	if len(results) != 1 {
+		t.Fatalf("game.investigation - want: %d, have: %d", 1, len(results))
+	}
+
+	investigation := g.Investigations[0]
+	if results[0].Target != investigation.Target || results[0].Chief != g.Players[investigation.Target].Type.IsChief() {
+		t.Fatalf("game.investigation - want: %s %t, have: %s %t", investigation.Target, g.Players[investigation.Target].Type.IsChief(), results[0].Target, results[0].Chief)
+	}
+	mtx.Unlock()
+
+	_, have = testWinningGame(t, game.TypeHunter, game.OptionNone, hunt(map[game.PlayerType]game.PlayerType{
+		game.PlayerTypeHunterSpy:        game.PlayerTypeResistance,
+		game.PlayerTypeHunterResistance: game.PlayerTypeHunterSpy,
+	}))
+	if have != game.StatusWon {
+		t.Fatalf("accused resistance - want: '%s', have: '%s'", game.StatusWon.String(), have.String())
+	}
+
+	// the resistance hunter keeps the win by finding the spy chief as well
+	_, have = testWinningGame(t, game.TypeHunter, game.OptionNone, hunt(map[game.PlayerType]game.PlayerType{
+		game.PlayerTypeHunterSpy:        game.PlayerTypeChiefResistance,
+		game.PlayerTypeHunterResistance: game.PlayerTypeChiefSpy,
+	}))
+	if have != game.StatusWon {
+		t.Fatalf("both chiefs accused - want: '%s', have: '%s'", game.StatusWon.String(), have.String())
+	}
+}
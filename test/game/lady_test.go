@@ -0,0 +1,87 @@
+package game
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+func TestGameLady(t *testing.T) {
+	var mtx sync.Mutex
+	lps := map[string]*loopParameter{}
+
+	lady := func(lp *loopParameter) conn.MessageStruct {
+		mtx.Lock()
+		lps[lp.player.GetClient().ID] = lp
+		mtx.Unlock()
+
+		return conn.MessageStruct{
+			"lady": func(log logger.Logger, body []byte) error {
+				l := game.Lady{}
+				if err := json.Unmarshal(body, &l); err != nil {
+					t.Errorf("json.Unmarshal: %v", err)
+					return err
+				}
+
+				if l.Holder != lp.player.GetClient().ID || len(l.Targets) == 0 {
+					return nil
+				}
+
+				// check a spy whenever possible
+				target := l.Targets[0]
+				for _, v := range l.Targets {
+					if lp.g.Players[v].Type.IsSpy() {
+						target = v
+					}
+				}
+
+				lp.vsn.WriteMessage(conn.MessageSend{
+					Group: "game",
+					Name:  "lady",
+					Body:  target,
+				})
+
+				return nil
+			},
+		}
+	}
+
+	g, have := testWinningGame(t, game.TypeBasic, game.OptionLady, lady)
+	if have != game.StatusWon {
+		t.Fatalf("want: '%s', have: '%s'", game.StatusWon.String(), have.String())
+	}
+
+	// the game ends after the 3rd round, so the lady is only used after the 2nd round
+	if len(g.Lady.History) != 1 {
+		t.Fatalf("len(g.Lady.History) - want: %d, have: %d", 1, len(g.Lady.History))
+	}
+
+	check := g.Lady.History[0]
+	if g.Lady.Holder != check.Target {
+		t.Fatalf("the token did not pass to the target - want: %s, have: %s", check.Target, g.Lady.Holder)
+	}
+
+	if !g.Players[check.Target].Type.IsSpy() {
+		return
+	}
+
+	for id, lp := range lps {
+		lp.mtx.Lock()
+		ptype := game.PlayerType(lp.testgame.Players[check.Target])
+		lp.mtx.Unlock()
+
+		if id == check.Holder {
+			if ptype != game.PlayerTypeSpy {
+				t.Fatalf("holder - want: %d, have: %d", game.PlayerTypeSpy, ptype)
+			}
+		} else if g.Players[id].Type == game.PlayerTypeResistance {
+			if ptype != game.PlayerTypeResistance {
+				t.Fatalf("resistance - want: %d, have: %d", game.PlayerTypeResistance, ptype)
+			}
+		}
+	}
+}
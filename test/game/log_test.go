@@ -0,0 +1,99 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+func TestGameLog(t *testing.T) {
+	mapconn := map[string]conn.Conn{}
+	for i := 0; i < 5; i++ {
+		b := bot.NewBot(bot.Random{})
+		defer b.Destroy()
+
+		mapconn[b.GetClient().ID] = b
+	}
+
+	g, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionNone)
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+	g.SetTimers(game.TimerPresets[game.TimersCasual])
+
+	done := make(chan game.Status)
+	go g.Run(done)
+
+	var status game.Status
+	select {
+	case status = <-done:
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out")
+	}
+
+	events := g.Log()
+	if len(events) < 2 {
+		t.Fatalf("len(events): %d", len(events))
+	}
+
+	first, last := events[0], events[len(events)-1]
+	if first.Type != game.EventRoles || len(first.Roles) != 5 {
+		t.Fatalf("first event - have: %+v", first)
+	}
+
+	for id, pt := range first.Roles {
+		if g.Players[id].Type != pt {
+			t.Fatalf("roles - want: %d, have: %d", g.Players[id].Type, pt)
+		}
+	}
+
+	if last.Type != game.EventEnd || last.Status != status || len(last.Players) != len(g.GetWinners()) {
+		t.Fatalf("last event - have: %+v", last)
+	}
+
+	count := map[game.EventType]int{}
+	for k, v := range events {
+		if v.Index != k {
+			t.Fatalf("events[%d].Index: %d", k, v.Index)
+		}
+
+		if k > 0 && v.Time.Before(events[k-1].Time) {
+			t.Fatalf("events[%d] happened before events[%d]", k, k-1)
+		}
+
+		count[v.Type]++
+	}
+
+	// every proposed team gets a captain, and a vote from each player
+	teams, cards, rounds := 0, 0, 0
+	for _, r := range g.Rounds {
+		for _, m := range r.Missions {
+			if len(m.Assignees) > 0 {
+				teams++
+			}
+
+			if m.IsAccepted() {
+				cards += len(m.Assignees)
+			}
+		}
+
+		if r.GetConculsion() != game.StatusDefault {
+			rounds++
+		}
+	}
+
+	if count[game.EventCaptain] != teams || count[game.EventTeam] != teams || count[game.EventVote] != teams*5 {
+		t.Fatalf("teams - want: %d, have: %v", teams, count)
+	}
+
+	if count[game.EventCard] != cards {
+		t.Fatalf("cards - want: %d, have: %d", cards, count[game.EventCard])
+	}
+
+	if count[game.EventRound] != rounds {
+		t.Fatalf("rounds - want: %d, have: %d", rounds, count[game.EventRound])
+	}
+}
@@ -0,0 +1,110 @@
+package game
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+func TestGameLoyalty(t *testing.T) {
+	var mtx sync.Mutex
+	// the player types before the game has started
+	types := map[string]game.PlayerType{}
+	drawn := []bool{}
+
+	loyalty := func(lp *loopParameter) conn.MessageStruct {
+		mtx.Lock()
+		types[lp.player.GetClient().ID] = lp.player.Type
+		mtx.Unlock()
+
+		return conn.MessageStruct{
+			"loyalty": func(log logger.Logger, body []byte) error {
+				l := game.Loyalty{}
+				if err := json.Unmarshal(body, &l); err != nil {
+					t.Errorf("json.Unmarshal: %v", err)
+					return err
+				}
+
+				mtx.Lock()
+				drawn = l.Drawn
+				mtx.Unlock()
+
+				return nil
+			},
+			// don't assassinate merlin, so the resistance wins
+			"assassinate": func(log logger.Logger, body []byte) error {
+				assassination := game.Assassination{}
+				if err := json.Unmarshal(body, &assassination); err != nil {
+					t.Errorf("json.Unmarshal: %v", err)
+					return err
+				}
+
+				if len(assassination.Assassins) == 0 || assassination.Assassins[0] != lp.player.GetClient().ID {
+					return nil
+				}
+
+				for _, v := range assassination.Targets {
+					if lp.g.Players[v].Type != game.PlayerTypeMerlin {
+						lp.vsn.WriteMessage(conn.MessageSend{
+							Group: "game",
+							Name:  "assassinate",
+							Body:  v,
+						})
+						break
+					}
+				}
+
+				return nil
+			},
+		}
+	}
+
+	g, have := testWinningGame(t, game.TypeAvalon, game.OptionLancelot, loyalty)
+	if have != game.StatusWon {
+		t.Fatalf("want: '%s', have: '%s'", game.StatusWon.String(), have.String())
+	}
+
+	// the game ends after the 3rd round, so only one card is drawn
+	if len(g.Loyalty.Drawn) != 1 {
+		t.Fatalf("len(g.Loyalty.Drawn) - want: %d, have: %d", 1, len(g.Loyalty.Drawn))
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if len(drawn) != 1 || drawn[0] != g.Loyalty.Drawn[0] {
+		t.Fatalf("game.loyalty - want: %v, have: %v", g.Loyalty.Drawn, drawn)
+	}
+
+	winners := map[string]bool{}
+	for _, v := range g.GetWinners() {
+		winners[v] = true
+	}
+
+	for id, ptype := range types {
+		if ptype != game.PlayerTypeLancelotGood && ptype != game.PlayerTypeLancelotEvil {
+			continue
+		}
+
+		want := ptype
+		if g.Loyalty.Drawn[0] {
+			if ptype == game.PlayerTypeLancelotGood {
+				want = game.PlayerTypeLancelotEvil
+			} else {
+				want = game.PlayerTypeLancelotGood
+			}
+		}
+
+		if have := g.Players[id].Type; have != want {
+			t.Fatalf("lancelot - want: %d, have: %d", want, have)
+		}
+
+		if winners[id] != (want == game.PlayerTypeLancelotGood) {
+			t.Fatalf("lancelot with player type: %d should've won: %t", want, !winners[id])
+		}
+	}
+}
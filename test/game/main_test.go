@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gobwas/ws"
 	"github.com/gobwas/ws/wsutil"
@@ -16,6 +17,7 @@ import (
 	"github.com/toms1441/resistance-server/internal/conn"
 	"github.com/toms1441/resistance-server/internal/discord"
 	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/logger"
 )
 
 var sampleuser = discord.User{
@@ -128,6 +130,81 @@ func testGameLoop(start int, end int, callback func(*game.Game, game.Type, game.
 
 }
 
+// testWinningGame runs a game of 5 players where the resistance recruits an all resistance team on each mission, so the resistance wins every round.
+// commands returns the commands that get added on top of the ones in TestGameRun, it can be nil.
+func testWinningGame(t *testing.T, gtype game.Type, goption game.Option, commands func(lp *loopParameter) conn.MessageStruct) (*game.Game, game.Status) {
+	mapconn := map[string]conn.Conn{}
+	for _, v := range cn[:5] {
+		mapconn[v.GetClient().ID] = v
+	}
+
+	g, err := game.NewGame(mapconn, gtype.Common(), goption)
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+
+	players := []int{}
+	for i := 0; i < 5; i++ {
+		vsn := sn[i]
+
+		player, ok := g.Players[cn[i].GetClient().ID]
+		if !ok {
+			continue
+		}
+
+		players = append(players, i)
+		testgame := &TestGame{}
+
+		lp := &loopParameter{
+			g:       g,
+			gtype:   gtype,
+			goption: goption,
+
+			testgame: testgame,
+			rounds:   []game.Round{},
+			players:  &players,
+			vsn:      vsn,
+			player:   player,
+			index:    2,
+		}
+
+		strct := conn.MessageStruct{
+			"choose": getChooseFunc(lp),
+			"vote":   getVoteFunc(lp),
+			"decide": getDecideFunc(lp),
+			"round":  getRoundFunc(lp),
+			"get": func(log logger.Logger, body []byte) error {
+				lp.mtx.Lock()
+				defer lp.mtx.Unlock()
+
+				json.Unmarshal(body, testgame)
+				return nil
+			},
+		}
+
+		if commands != nil {
+			for k, v := range commands(lp) {
+				strct[k] = v
+			}
+		}
+
+		vsn.AddCommand("game", strct)
+		defer vsn.RemoveCommandsByGroup("game")
+	}
+
+	done := make(chan game.Status)
+	go g.Run(done)
+
+	select {
+	case have := <-done:
+		return g, have
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out")
+	}
+
+	return g, game.StatusDefault
+}
+
 func TestMain(m *testing.M) {
 	v := sampleuser
 
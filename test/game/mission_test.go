@@ -45,13 +45,13 @@ func getChooseFunc(lp *loopParameter) conn.MessageCallback {
 					if lp.index == 1 {
 						// for index 1, we want as many resistance as possible.
 						// we'll add a spy later
-						if playa.Type == game.PlayerTypeSpy || playa.Type == game.PlayerTypeMorgana {
+						if playa.Type.IsSpy() {
 							continue
 						}
 						// for index 2, we want as many resistance as possible.
 						// no spies for this one
 					} else if lp.index == 2 {
-						if playa.Type != game.PlayerTypeResistance && playa.Type != game.PlayerTypeMerlin {
+						if playa.Type.IsSpy() {
 							continue
 						}
 					}
@@ -5,6 +5,7 @@ package game
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
@@ -225,3 +226,113 @@ func TestGameMessageSend(t *testing.T) {
 		}
 	})
 }
+
+func TestGameSpyRoles(t *testing.T) {
+	spiesmap := map[int]int{
+		7:  3,
+		8:  3,
+		9:  3,
+		10: 4,
+	}
+
+	goption := game.OptionAssassin.Add(game.OptionMordred).Add(game.OptionOberon)
+
+	mapconn := map[string]conn.Conn{}
+	for _, v := range cn[:5] {
+		mapconn[v.GetClient().ID] = v
+	}
+
+	// 5 players only have 2 spies
+	_, err := game.NewGame(mapconn, game.TypeAvalon.Common(), goption)
+	if err != game.ErrSpyRoles {
+		t.Fatalf("game.NewGame - want: %v, have: %v", game.ErrSpyRoles, err)
+	}
+
+	for i := 7; i <= 10; i++ {
+		mapconn := map[string]conn.Conn{}
+		for _, v := range cn[:i] {
+			mapconn[v.GetClient().ID] = v
+		}
+
+		g, err := game.NewGame(mapconn, game.TypeAvalon.Common(), goption)
+		if err != nil {
+			t.Fatalf("game.NewGame: %v", err)
+		}
+
+		have := map[game.PlayerType]int{}
+		spies := 0
+		for _, v := range g.Players {
+			have[v.Type]++
+			if v.Type.IsSpy() {
+				spies++
+			}
+		}
+
+		if spies != spiesmap[i] {
+			t.Fatalf("player len: %d, spies - want: %d, have: %d", i, spiesmap[i], spies)
+		}
+
+		for _, v := range []game.PlayerType{game.PlayerTypeAssassin, game.PlayerTypeMordred, game.PlayerTypeOberon} {
+			if have[v] != 1 {
+				t.Fatalf("player len: %d, player type: %d - want: %d, have: %d", i, v, 1, have[v])
+			}
+		}
+	}
+}
+
+func TestGameAvalonOptions(t *testing.T) {
+	mapconn := map[string]conn.Conn{}
+	for _, v := range cn[:7] {
+		mapconn[v.GetClient().ID] = v
+	}
+
+	for _, o := range []game.Option{game.OptionPercival, game.OptionMorgana, game.OptionAssassin, game.OptionOberon, game.OptionMordred, game.OptionLancelot} {
+		for _, typ := range []game.Type{game.TypeBasic, game.TypeOriginal, game.TypeHunter, game.TypeTrumpmode} {
+			if _, err := game.NewGame(mapconn, typ.Common(), o); !errors.Is(err, game.ErrAvalonOption) {
+				t.Fatalf("%s %s - want: %v, have: %v", typ, o, game.ErrAvalonOption, err)
+			}
+		}
+
+		if _, err := game.NewGame(mapconn, game.TypeAvalon.Common(), o); err != nil {
+			t.Fatalf("%s - game.NewGame: %v", o, err)
+		}
+	}
+
+	// the lady of the lake isn't an avalon role
+	if _, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionLady); err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+}
+
+func TestGameLancelot(t *testing.T) {
+	for i := 5; i <= 10; i++ {
+		mapconn := map[string]conn.Conn{}
+		for _, v := range cn[:i] {
+			mapconn[v.GetClient().ID] = v
+		}
+
+		g, err := game.NewGame(mapconn, game.TypeAvalon.Common(), game.OptionLancelot)
+		if err != nil {
+			t.Fatalf("game.NewGame: %v", err)
+		}
+
+		have := map[game.PlayerType]int{}
+		for _, v := range g.Players {
+			have[v.Type]++
+		}
+
+		if have[game.PlayerTypeLancelotGood] != 1 || have[game.PlayerTypeLancelotEvil] != 1 {
+			t.Fatalf("player len: %d, lancelots - want: 1 good 1 evil, have: %d good %d evil", i, have[game.PlayerTypeLancelotGood], have[game.PlayerTypeLancelotEvil])
+		}
+	}
+
+	mapconn := map[string]conn.Conn{}
+	for _, v := range cn[:5] {
+		mapconn[v.GetClient().ID] = v
+	}
+
+	_, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionLancelot)
+	if err == nil {
+		t.Fatal("lancelot should only work with type avalon")
+	}
+}
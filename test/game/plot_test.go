@@ -0,0 +1,178 @@
+package game
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+func TestGamePlot(t *testing.T) {
+	var mtx sync.Mutex
+	eyes := 0
+
+	plot := func(lp *loopParameter) conn.MessageStruct {
+		id := lp.player.GetClient().ID
+		choose := getChooseFunc(lp)
+		// the cards that this player holds, as of the last game.deal
+		hand := []game.PlotCard{}
+
+		has := func(card game.PlotCard) bool {
+			lp.mtx.Lock()
+			defer lp.mtx.Unlock()
+
+			for _, v := range hand {
+				if v == card {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		write := func(name string, body interface{}) {
+			lp.vsn.WriteMessage(conn.MessageSend{
+				Group: "game",
+				Name:  name,
+				Body:  body,
+			})
+		}
+
+		// next returns the player that sits after this player
+		next := func() string {
+			for k, v := range lp.g.Seats {
+				if v == id {
+					return lp.g.Seats[(k+1)%len(lp.g.Seats)]
+				}
+			}
+
+			return ""
+		}
+
+		return conn.MessageStruct{
+			"deal": func(log logger.Logger, body []byte) error {
+				pl := game.Plot{}
+				if err := json.Unmarshal(body, &pl); err != nil {
+					t.Errorf("json.Unmarshal: %v", err)
+					return err
+				}
+
+				lp.mtx.Lock()
+				hand = pl.Hands[id]
+				lp.mtx.Unlock()
+
+				// the captain gives the cards to the next player
+				if pl.Captain == id && len(pl.Dealt) > 0 {
+					write("give", game.PlotPlay{
+						Card:   pl.Dealt[0],
+						Target: next(),
+					})
+				}
+
+				// immediate cards are played on the next player
+				for _, card := range []game.PlotCard{game.PlotCardOverheardConversation, game.PlotCardOpenUp} {
+					if has(card) {
+						write("plot", game.PlotPlay{
+							Card:   card,
+							Target: next(),
+						})
+					}
+				}
+
+				return nil
+			},
+			"choose": func(log logger.Logger, body []byte) error {
+				var captain string
+				json.Unmarshal(body, &captain)
+
+				if captain != id && has(game.PlotCardStrongLeader) {
+					write("plot", game.PlotPlay{
+						Card: game.PlotCardStrongLeader,
+					})
+				}
+
+				return choose(log, body)
+			},
+			"react": func(log logger.Logger, body []byte) error {
+				holders := []string{}
+				if err := json.Unmarshal(body, &holders); err != nil {
+					t.Errorf("json.Unmarshal: %v", err)
+					return err
+				}
+
+				for _, v := range holders {
+					if v != id {
+						continue
+					}
+
+					// keep an eye on an assignee, and never play no confidence
+					if has(game.PlotCardKeepingCloseEye) {
+						play := game.PlotPlay{
+							Card: game.PlotCardKeepingCloseEye,
+						}
+
+						for _, round := range lp.g.Rounds {
+							for _, mission := range round.Missions {
+								for _, assignee := range mission.Assignees {
+									if assignee != id {
+										play.Target = assignee
+									}
+								}
+							}
+						}
+
+						write("plot", play)
+						time.Sleep(time.Millisecond * 10)
+					}
+
+					write("plot", game.PlotPlay{
+						Card: game.PlotCardNone,
+					})
+				}
+
+				return nil
+			},
+			"eye": func(log logger.Logger, body []byte) error {
+				mtx.Lock()
+				eyes++
+				mtx.Unlock()
+
+				return nil
+			},
+		}
+	}
+
+	g, have := testWinningGame(t, game.TypeOriginal, game.OptionNone, plot)
+	if have != game.StatusWon {
+		t.Fatalf("want: '%s', have: '%s'", game.StatusWon.String(), have.String())
+	}
+
+	// 5 players get 1 card per round, and the game ends after the 3rd round
+	cards := len(g.Plot.History)
+	for _, v := range g.Plot.Hands {
+		cards += len(v)
+	}
+
+	if cards != 3 {
+		t.Fatalf("cards - want: %d, have: %d", 3, cards)
+	}
+
+	want := 0
+	for _, v := range g.Plot.History {
+		if v.Card == game.PlotCardKeepingCloseEye {
+			want++
+		}
+	}
+
+	time.Sleep(time.Millisecond * 10)
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	if eyes != want {
+		t.Fatalf("game.eye - want: %d, have: %d", want, eyes)
+	}
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/logger"
+)
+
+func TestGameRebind(t *testing.T) {
+	mapconn := map[string]conn.Conn{}
+	for _, v := range cn[:5] {
+		mapconn[v.GetClient().ID] = v
+	}
+
+	g, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionNone)
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+
+	g.SetTimers(game.Timers{
+		Choose: time.Millisecond * 300,
+		Vote:   time.Millisecond * 20,
+		Decide: time.Millisecond * 20,
+	})
+
+	captains := make(chan string, 1)
+	for _, vsn := range sn[:5] {
+		vsn.AddCommand("game", conn.MessageStruct{
+			"choose": func(log logger.Logger, body []byte) error {
+				var captain string
+				json.Unmarshal(body, &captain)
+
+				select {
+				case captains <- captain:
+				default:
+				}
+
+				return nil
+			},
+		})
+		defer vsn.RemoveCommandsByGroup("game")
+	}
+
+	done := make(chan game.Status)
+	go g.Run(done)
+
+	var captain string
+	select {
+	case captain = <-captains:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for game.choose")
+	}
+
+	// the captain reconnects with a new connection
+	nsn, ncn := conn.NewMockConnHelper(client.Client{
+		User: g.Players[captain].GetClient().User,
+	})
+
+	get := make(chan bool, 1)
+	nsn.AddCommand("game", conn.MessageStruct{
+		"get": func(log logger.Logger, body []byte) error {
+			select {
+			case get <- true:
+			default:
+			}
+
+			return nil
+		},
+	})
+
+	if err := g.Rebind(ncn); err != nil {
+		t.Fatalf("g.Rebind: %v", err)
+	}
+
+	select {
+	case <-get:
+	case <-time.After(time.Millisecond * 100):
+		t.Fatal("game.get was not sent to the new connection")
+	}
+
+	// game.choose got added to the new connection
+	team := g.Seats[:g.Rounds[0].Assignees]
+	nsn.WriteMessage(conn.MessageSend{
+		Group: "game",
+		Name:  "choose",
+		Body:  team,
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 3):
+		t.Fatal("timed out")
+	}
+
+	have := g.Rounds[0].Missions[0].Assignees
+	if len(have) != len(team) {
+		t.Fatalf("assignees - want: %v, have: %v", team, have)
+	}
+
+	for k := range team {
+		if team[k] != have[k] {
+			t.Fatalf("assignees - want: %v, have: %v", team, have)
+		}
+	}
+
+	_, stranger := conn.NewMockConnHelper(client.Client{
+		User: sampleusers[9],
+	})
+
+	if err := g.Rebind(stranger); err != game.ErrInvalidPlayer {
+		t.Fatalf("want: %v, have: %v", game.ErrInvalidPlayer, err)
+	}
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+func TestRulesetDefault(t *testing.T) {
+	if err := game.DefaultRuleset.Validate(); err != nil {
+		t.Fatalf("game.DefaultRuleset.Validate: %v", err)
+	}
+
+	// official amount of assignees and spies, the 4th round of 7 players or more needs 2 failures
+	want := map[int]struct {
+		assignees [5]uint8
+		spies     int
+	}{
+		5:  {[5]uint8{2, 3, 2, 3, 3}, 2},
+		6:  {[5]uint8{2, 3, 4, 3, 4}, 2},
+		7:  {[5]uint8{2, 3, 3, 4, 4}, 3},
+		8:  {[5]uint8{3, 4, 4, 5, 5}, 3},
+		9:  {[5]uint8{3, 4, 4, 5, 5}, 3},
+		10: {[5]uint8{3, 4, 4, 5, 5}, 4},
+	}
+
+	for players, v := range want {
+		rules, ok := game.DefaultRuleset[players]
+		if !ok {
+			t.Fatalf("player len: %d - no rules", players)
+		}
+
+		if rules.Assignees != v.assignees || rules.Spies != v.spies {
+			t.Fatalf("player len: %d - want: %v %d, have: %v %d", players, v.assignees, v.spies, rules.Assignees, rules.Spies)
+		}
+
+		minfailure := uint8(1)
+		if players >= 7 {
+			minfailure = 2
+		}
+
+		if rules.MinFailure[3] != minfailure {
+			t.Fatalf("player len: %d, double-fail - want: %d, have: %d", players, minfailure, rules.MinFailure[3])
+		}
+	}
+}
+
+func TestRulesValidate(t *testing.T) {
+	rules := game.DefaultRuleset[5]
+
+	invalid := map[error]func(r *game.Rules){
+		game.ErrRulesAssignees:  func(r *game.Rules) { r.Assignees[0] = 6 },
+		game.ErrRulesMinFailure: func(r *game.Rules) { r.MinFailure[0] = 3 },
+		game.ErrRulesSpies:      func(r *game.Rules) { r.Spies = 3 },
+		game.ErrRulesProposals:  func(r *game.Rules) { r.Proposals = 6 },
+	}
+
+	for want, change := range invalid {
+		r := rules
+		change(&r)
+
+		if have := r.Validate(5); !errors.Is(have, want) {
+			t.Fatalf("want: %v, have: %v", want, have)
+		}
+	}
+
+	if have := rules.Validate(11); have != game.ErrRulesPlayers {
+		t.Fatalf("want: %v, have: %v", game.ErrRulesPlayers, have)
+	}
+}
+
+func TestGameWithRuleset(t *testing.T) {
+	mapconn := map[string]conn.Conn{}
+	for _, v := range cn[:5] {
+		mapconn[v.GetClient().ID] = v
+	}
+
+	rules := game.DefaultRuleset[5]
+	rules.Assignees = [5]uint8{2, 2, 2, 2, 2}
+	rules.Proposals = 3
+
+	g, err := game.NewGameWithRuleset(mapconn, game.TypeBasic.Common(), game.OptionNone, game.Ruleset{5: rules})
+	if err != nil {
+		t.Fatalf("game.NewGameWithRuleset: %v", err)
+	}
+
+	for k, v := range g.Rounds {
+		if v.Assignees != 2 || v.Proposals != 3 {
+			t.Fatalf("round %d - want: %d %d, have: %d %d", k, 2, 3, v.Assignees, v.Proposals)
+		}
+	}
+
+	// the ruleset doesn't have rules for 6 players
+	mapconn[cn[5].GetClient().ID] = cn[5]
+	_, err = game.NewGameWithRuleset(mapconn, game.TypeBasic.Common(), game.OptionNone, game.Ruleset{5: rules})
+	if !errors.Is(err, game.ErrRuleset) {
+		t.Fatalf("want: %v, have: %v", game.ErrRuleset, err)
+	}
+}
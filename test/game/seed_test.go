@@ -0,0 +1,139 @@
+package game
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+func TestGameSeed(t *testing.T) {
+	mapconn := map[string]conn.Conn{}
+	for _, v := range cn[:7] {
+		mapconn[v.GetClient().ID] = v
+	}
+
+	newGame := func(settings ...game.Setting) *game.Game {
+		g, err := game.NewGame(mapconn, game.TypeAvalon.Common(), game.OptionPercival|game.OptionMorgana, settings...)
+		if err != nil {
+			t.Fatalf("game.NewGame: %v", err)
+		}
+
+		return g
+	}
+
+	roles := func(g *game.Game) map[string]game.PlayerType {
+		types := map[string]game.PlayerType{}
+		for k, v := range g.Players {
+			types[k] = v.Type
+		}
+
+		return types
+	}
+
+	// the same seed gives the same seats and roles
+	seed := game.Seed{1, 4, 4, 1}
+	g1, g2 := newGame(game.WithSeed(seed)), newGame(game.WithSeed(seed))
+	if g1.Seed == nil || *g1.Seed != seed {
+		t.Fatalf("g.Seed - want: %v, have: %v", seed, g1.Seed)
+	}
+
+	if !reflect.DeepEqual(g1.Seats, g2.Seats) {
+		t.Fatalf("g.Seats - want: %v, have: %v", g1.Seats, g2.Seats)
+	}
+
+	if !reflect.DeepEqual(roles(g1), roles(g2)) {
+		t.Fatalf("g.Players - want: %v, have: %v", roles(g1), roles(g2))
+	}
+
+	// a game without a seed uses crypto/rand, so there's nothing to reproduce it with
+	if g3 := newGame(); g3.Seed != nil {
+		t.Fatalf("g.Seed: %v", g3.Seed)
+	}
+
+	// the seed is stored in hex, i.e in replays
+	text, err := seed.MarshalText()
+	if err != nil {
+		t.Fatalf("seed.MarshalText: %v", err)
+	}
+
+	have := game.Seed{}
+	if err := have.UnmarshalText(text); err != nil || have != seed {
+		t.Fatalf("seed.UnmarshalText - want: %v, have: %v, err: %v", seed, have, err)
+	}
+
+	if err := have.UnmarshalText([]byte("1441")); err != game.ErrSeed {
+		t.Fatalf("want: %v, have: %v", game.ErrSeed, err)
+	}
+}
+
+func TestGameSeedHidden(t *testing.T) {
+	var mtx sync.Mutex
+	msgs := []conn.MessageSend{}
+
+	mapconn := map[string]conn.Conn{}
+	for _, v := range cn[:5] {
+		mapconn[v.GetClient().ID] = recordConn{
+			Conn: v,
+			mtx:  &mtx,
+			msgs: &msgs,
+		}
+	}
+
+	g, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionNone, game.WithSeed(game.Seed{1, 4, 4, 1}))
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+
+	g.Send()
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if len(msgs) != 5 {
+		t.Fatalf("game.get - want: %d, have: %d", 5, len(msgs))
+	}
+
+	for _, v := range msgs {
+		body, ok := v.Body.(map[string]interface{})
+		if !ok {
+			t.Fatalf("game.get body: %T", v.Body)
+		}
+
+		if _, ok := body["seed"]; ok {
+			t.Fatal("the seed was sent to a player")
+		}
+	}
+}
+
+func TestGameSeats(t *testing.T) {
+	mapconn := bots(t)
+
+	seats := []string{}
+	for id := range mapconn {
+		seats = append(seats, id)
+	}
+
+	g, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionNone, game.WithSeats(seats))
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+
+	if !reflect.DeepEqual(g.Seats, seats) {
+		t.Fatalf("g.Seats - want: %v, have: %v", seats, g.Seats)
+	}
+
+	invalid := [][]string{
+		seats[1:],
+		append([]string{seats[0]}, seats[:4]...),
+		append([]string{"stranger"}, seats[1:]...),
+	}
+
+	for _, v := range invalid {
+		if _, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionNone, game.WithSeats(v)); err != game.ErrSeats {
+			t.Fatalf("%v - want: %v, have: %v", v, game.ErrSeats, err)
+		}
+	}
+}
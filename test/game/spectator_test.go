@@ -0,0 +1,213 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/discord"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+// spectatorConn is a conn.Conn that records every message that gets written to it.
+type spectatorConn struct {
+	conn.Conn
+
+	mtx  sync.Mutex
+	msgs []conn.MessageRecv
+}
+
+func newSpectatorConn(id string) *spectatorConn {
+	_, c := conn.NewMockConnHelper(client.Client{
+		User: discord.User{
+			ID:            id,
+			Username:      "Spectator",
+			Discriminator: "0000",
+		},
+	})
+
+	return &spectatorConn{Conn: c}
+}
+
+func (s *spectatorConn) WriteMessage(ms conn.MessageSend) error {
+	body, err := json.Marshal(ms)
+	if err != nil {
+		return err
+	}
+
+	s.WriteBytes(body)
+	return nil
+}
+
+func (s *spectatorConn) WriteBytes(body []byte) {
+	msg := conn.MessageRecv{}
+	json.Unmarshal(body, &msg)
+
+	s.mtx.Lock()
+	s.msgs = append(s.msgs, msg)
+	s.mtx.Unlock()
+}
+
+// gets returns the players of every game.get that was sent to the spectator.
+func (s *spectatorConn) gets() []map[string]game.PlayerType {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	gets := []map[string]game.PlayerType{}
+	for _, v := range s.msgs {
+		if v.Group == "game" && v.Name == "get" {
+			players := struct {
+				Players map[string]game.PlayerType `json:"players"`
+			}{}
+			json.Unmarshal(v.Body, &players)
+
+			gets = append(gets, players.Players)
+		}
+	}
+
+	return gets
+}
+
+func (s *spectatorConn) len() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return len(s.msgs)
+}
+
+func TestGameSpectator(t *testing.T) {
+	mapconn := bots(t)
+
+	var player conn.Conn
+	for _, v := range mapconn {
+		player = v
+	}
+
+	g, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionNone)
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+	g.SetTimers(game.TimerPresets[game.TimersCasual])
+
+	spectator, caster := newSpectatorConn("spectator"), newSpectatorConn("caster")
+
+	if err := g.AddSpectator(player); !errors.Is(err, game.ErrSpectator) {
+		t.Fatalf("want: %v, have: %v", game.ErrSpectator, err)
+	}
+
+	if err := g.AddSpectator(spectator); err != nil {
+		t.Fatalf("g.AddSpectator: %v", err)
+	}
+
+	if err := g.AddSpectator(spectator); !errors.Is(err, game.ErrSpectator) {
+		t.Fatalf("want: %v, have: %v", game.ErrSpectator, err)
+	}
+
+	if err := g.AddCaster(caster, 0); !errors.Is(err, game.ErrCasterDelay) {
+		t.Fatalf("want: %v, have: %v", game.ErrCasterDelay, err)
+	}
+
+	delay := time.Millisecond * 200
+	if err := g.AddCaster(caster, delay); err != nil {
+		t.Fatalf("g.AddCaster: %v", err)
+	}
+
+	done := make(chan game.Status)
+	go g.Run(done)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out")
+	}
+
+	// the spectator can't play
+	if err := spectator.ExecuteCommand("game", "vote", []byte("true")); err == nil {
+		t.Fatal("the spectator was able to vote")
+	}
+
+	gets := spectator.gets()
+	if len(gets) == 0 {
+		t.Fatal("the spectator didn't get the game")
+	}
+
+	for _, players := range gets {
+		if len(players) != 5 {
+			t.Fatalf("len(players): %d", len(players))
+		}
+
+		for id, pt := range players {
+			if pt != game.PlayerTypeResistance {
+				t.Fatalf("players[%s] - want: %d, have: %d", id, game.PlayerTypeResistance, pt)
+			}
+		}
+	}
+
+	// the caster gets everything the spectator got, only later
+	all := spectator.len()
+	deadline := time.After(delay * 5)
+	for caster.len() < all {
+		select {
+		case <-deadline:
+			t.Fatalf("caster - want: %d messages, have: %d", all, caster.len())
+		case <-time.After(time.Millisecond * 10):
+		}
+	}
+
+	for _, players := range caster.gets() {
+		for id, pt := range players {
+			if g.Players[id].Type != pt {
+				t.Fatalf("players[%s] - want: %d, have: %d", id, g.Players[id].Type, pt)
+			}
+		}
+	}
+}
+
+func TestGameCasterDelay(t *testing.T) {
+	g, err := game.NewGame(bots(t), game.TypeBasic.Common(), game.OptionNone)
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+
+	caster := newSpectatorConn("caster")
+	if err := g.AddCaster(caster, time.Millisecond*100); err != nil {
+		t.Fatalf("g.AddCaster: %v", err)
+	}
+
+	time.Sleep(time.Millisecond * 20)
+	if caster.len() != 0 {
+		t.Fatal("the caster got the game before the delay")
+	}
+
+	time.Sleep(time.Millisecond * 150)
+	if caster.len() != 1 {
+		t.Fatalf("want: %d, have: %d", 1, caster.len())
+	}
+
+	// a removed caster doesn't get what's left
+	g.Send()
+	g.RemoveSpectator(caster.GetClient().ID)
+
+	time.Sleep(time.Millisecond * 150)
+	if caster.len() != 1 {
+		t.Fatalf("want: %d, have: %d", 1, caster.len())
+	}
+}
+
+// bots returns five bots, that get destroyed once the test is finished.
+func bots(t *testing.T) map[string]conn.Conn {
+	conns := map[string]conn.Conn{}
+	for i := 0; i < 5; i++ {
+		b := bot.NewBot(bot.Random{})
+		t.Cleanup(b.Destroy)
+
+		conns[b.GetClient().ID] = b
+	}
+
+	return conns
+}
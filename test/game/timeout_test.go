@@ -0,0 +1,140 @@
+package game
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+func TestGameTimeout(t *testing.T) {
+	timers := game.Timers{
+		Choose: time.Millisecond * 20,
+		Vote:   time.Millisecond * 20,
+		Decide: time.Millisecond * 20,
+	}
+
+	// run starts a game where nobody does anything, it returns the game and the game.timeout messages that a player got
+	run := func(policies game.Policies) (*game.Game, game.Status, []conn.MessageSend) {
+		var mtx sync.Mutex
+		msgs := []conn.MessageSend{}
+
+		mapconn := map[string]conn.Conn{}
+		for k, v := range cn[:5] {
+			rc := recordConn{
+				Conn: v,
+				mtx:  &mtx,
+				msgs: &[]conn.MessageSend{},
+			}
+
+			if k == 0 {
+				rc.msgs = &msgs
+			}
+
+			mapconn[v.GetClient().ID] = rc
+		}
+
+		g, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionNone)
+		if err != nil {
+			t.Fatalf("game.NewGame: %v", err)
+		}
+
+		g.SetTimers(timers)
+		if err := g.SetPolicies(policies); err != nil {
+			t.Fatalf("g.SetPolicies: %v", err)
+		}
+
+		done := make(chan game.Status)
+		go g.Run(done)
+
+		var have game.Status
+		select {
+		case have = <-done:
+		case <-time.After(time.Second * 2):
+			t.Fatal("timed out")
+		}
+
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		timeouts := []conn.MessageSend{}
+		for _, v := range msgs {
+			if v.Name == "timeout" {
+				timeouts = append(timeouts, v)
+			}
+		}
+
+		return g, have, timeouts
+	}
+
+	// missed cards count as success, so the resistance wins 3 rounds
+	_, have, timeouts := run(game.Policies{Vote: game.VoteApprove, Decide: game.DecideSuccess})
+	if have != game.StatusWon {
+		t.Fatalf("approve - want: '%s', have: '%s'", game.StatusWon.String(), have.String())
+	}
+
+	// every proposal times out in choose, vote and decide
+	if len(timeouts) != 3*3 {
+		t.Fatalf("approve, game.timeout - want: %d, have: %d", 3*3, len(timeouts))
+	}
+
+	// every proposal gets rejected, so the spies win the first round
+	_, have, timeouts = run(game.Policies{Vote: game.VoteReject})
+	if have != game.StatusLost {
+		t.Fatalf("reject - want: '%s', have: '%s'", game.StatusLost.String(), have.String())
+	}
+
+	// 5 proposals that time out in choose and vote
+	if len(timeouts) != 5*2 {
+		t.Fatalf("reject, game.timeout - want: %d, have: %d", 5*2, len(timeouts))
+	}
+
+	// missed votes don't count by default, so every proposal gets declined
+	g, have, timeouts := run(game.Policies{})
+	if have != game.StatusLost {
+		t.Fatalf("none - want: '%s', have: '%s'", game.StatusLost.String(), have.String())
+	}
+
+	// only the captains that didn't choose, the missed votes weren't counted
+	if len(timeouts) != 5 {
+		t.Fatalf("none, game.timeout - want: %d, have: %d", 5, len(timeouts))
+	}
+
+	for _, v := range timeouts {
+		body, _ := v.Body.(map[string]interface{})
+		if body["phase"] != "choose" {
+			t.Fatalf("none, game.timeout - want: %s, have: %v", "choose", body["phase"])
+		}
+	}
+
+	for _, v := range g.Rounds[0].Missions {
+		if len(v.Accept) != 0 || len(v.Decline) != 0 {
+			t.Fatalf("none, votes - accept: %v, decline: %v", v.Accept, v.Decline)
+		}
+	}
+
+	// the missed cards of spies count as failure
+	g, _, _ = run(game.Policies{Vote: game.VoteApprove, Decide: game.DecideFail})
+	for k, v := range g.Rounds {
+		if v.Missions[0].IsEmpty() {
+			continue
+		}
+
+		spies := uint8(0)
+		for _, id := range v.Missions[0].Assignees {
+			if g.Players[id].Type.IsSpy() {
+				spies++
+			}
+		}
+
+		if v.Failure != spies {
+			t.Fatalf("round %d, failure - want: %d, have: %d", k, spies, v.Failure)
+		}
+	}
+
+	if err := (game.Policies{Vote: game.VoteReject + 1}).Validate(); err != game.ErrPolicies {
+		t.Fatalf("want: %v, have: %v", game.ErrPolicies, err)
+	}
+}
@@ -0,0 +1,108 @@
+package game
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+// recordConn is a conn.Conn that records every message that gets written to it.
+type recordConn struct {
+	conn.Conn
+
+	mtx  *sync.Mutex
+	msgs *[]conn.MessageSend
+}
+
+func (r recordConn) WriteBytes(body []byte) {
+	ms := conn.MessageSend{}
+	json.Unmarshal(body, &ms)
+
+	r.mtx.Lock()
+	*r.msgs = append(*r.msgs, ms)
+	r.mtx.Unlock()
+
+	r.Conn.WriteBytes(body)
+}
+
+func (r recordConn) WriteMessage(ms conn.MessageSend) error {
+	body, err := json.Marshal(ms)
+	if err != nil {
+		return err
+	}
+
+	r.WriteBytes(body)
+	return nil
+}
+
+func TestGameTimers(t *testing.T) {
+	var mtx sync.Mutex
+	msgs := []conn.MessageSend{}
+
+	mapconn := map[string]conn.Conn{}
+	for _, v := range cn[:5] {
+		mapconn[v.GetClient().ID] = recordConn{
+			Conn: v,
+			mtx:  &mtx,
+			msgs: &msgs,
+		}
+	}
+
+	g, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionNone)
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+
+	// nobody does anything, so every phase ends once the time runs out
+	timers := game.Timers{
+		Choose: time.Millisecond * 20,
+		Vote:   time.Millisecond * 20,
+		Decide: time.Millisecond * 20,
+	}
+	g.SetTimers(timers)
+
+	err = g.SetPolicies(game.Policies{Vote: game.VoteApprove})
+	if err != nil {
+		t.Fatalf("g.SetPolicies: %v", err)
+	}
+
+	start := time.Now()
+
+	done := make(chan game.Status)
+	go g.Run(done)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out")
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	phases := 0
+	for _, v := range msgs {
+		if v.Name != "choose" && v.Name != "vote" && v.Name != "decide" {
+			continue
+		}
+
+		phases++
+		if v.Deadline == nil {
+			t.Fatalf("game.%s has no deadline", v.Name)
+		}
+
+		if v.Deadline.Before(start) || v.Deadline.After(time.Now().Add(timers.Vote)) {
+			t.Fatalf("game.%s deadline out of range: %v", v.Name, v.Deadline)
+		}
+	}
+
+	// the missed votes count as approvals, so the resistance wins 3 rounds with a proposal each
+	// every proposal has a choosing, voting and deciding phase, sent to 5 players
+	if want := 3 * 3 * 5; phases != want {
+		t.Fatalf("phases - want: %d, have: %d", want, phases)
+	}
+}
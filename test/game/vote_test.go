@@ -0,0 +1,105 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+func TestGameVoteResult(t *testing.T) {
+	g, err := game.NewGame(bots(t), game.TypeBasic.Common(), game.OptionNone)
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+	g.SetTimers(game.TimerPresets[game.TimersCasual])
+
+	// every broadcast gets to the spectators
+	spectator := newSpectatorConn("spectator")
+	if err := g.AddSpectator(spectator); err != nil {
+		t.Fatalf("g.AddSpectator: %v", err)
+	}
+
+	done := make(chan game.Status)
+	go g.Run(done)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out")
+	}
+
+	results := []game.VoteResult{}
+	spectator.mtx.Lock()
+	for _, v := range spectator.msgs {
+		if v.Group == "game" && v.Name == "vote_result" {
+			result := game.VoteResult{}
+			err := json.Unmarshal(v.Body, &result)
+			if err != nil {
+				t.Fatalf("json.Unmarshal: %v", err)
+			}
+
+			results = append(results, result)
+		}
+	}
+	spectator.mtx.Unlock()
+
+	teams := 0
+	for _, r := range g.Rounds {
+		for _, m := range r.Missions {
+			if len(m.Assignees) > 0 {
+				teams++
+			}
+		}
+	}
+
+	if len(results) != teams {
+		t.Fatalf("want: %d, have: %d", teams, len(results))
+	}
+
+	for _, result := range results {
+		r := g.Rounds[result.Round]
+		m := r.Missions[result.Proposal]
+
+		if len(result.Votes) != len(g.Players) {
+			t.Fatalf("len(result.Votes) - want: %d, have: %d", len(g.Players), len(result.Votes))
+		}
+
+		for _, id := range m.Accept {
+			if !result.Votes[id] {
+				t.Fatalf("result.Votes[%s] - want: true, have: false", id)
+			}
+		}
+
+		if result.Accepted != m.IsAccepted() {
+			t.Fatalf("result.Accepted - want: %t, have: %t", m.IsAccepted(), result.Accepted)
+		}
+
+		// every team before this one in the round got declined
+		declined := result.Proposal
+		if !result.Accepted {
+			declined++
+		}
+
+		if want := int(r.Proposals) - declined; result.Remaining != want {
+			t.Fatalf("result.Remaining - want: %d, have: %d", want, result.Remaining)
+		}
+	}
+}
+
+func TestRoundRemaining(t *testing.T) {
+	r := game.Round{Proposals: 5}
+	if r.Remaining() != 5 {
+		t.Fatalf("want: %d, have: %d", 5, r.Remaining())
+	}
+
+	r.Missions[0] = game.Mission{Assignees: []string{"a", "b"}, Accept: []string{"a"}, Decline: []string{"b", "c"}}
+	r.Missions[1] = game.Mission{Assignees: []string{"a", "b"}, Accept: []string{"a", "b"}, Decline: []string{"c"}, Rejected: "c"}
+	r.Missions[2] = game.Mission{Assignees: []string{"a", "b"}, Accept: []string{"a", "b"}, Decline: []string{"c"}}
+
+	// a team rejected through PlotCardNoConfidence counts as declined
+	if r.Remaining() != 3 {
+		t.Fatalf("want: %d, have: %d", 3, r.Remaining())
+	}
+}
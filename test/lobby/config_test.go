@@ -1,8 +1,10 @@
 package lobby
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/toms1441/resistance-server/internal/game"
 	"github.com/toms1441/resistance-server/internal/lobby"
 )
 
@@ -31,3 +33,24 @@ func TestConfigValidate(t *testing.T) {
 		t.Fatalf("config.Validate: %v", err)
 	}
 }
+
+func TestConfigValidateRulesets(t *testing.T) {
+	c := lobby.DefaultConfig
+	c.Rulesets = map[string]game.Ruleset{
+		"house": {
+			5: game.DefaultRuleset[5],
+		},
+	}
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("c.Validate: %v", err)
+	}
+
+	rules := game.DefaultRuleset[5]
+	rules.Spies = 3
+	c.Rulesets["house"][5] = rules
+
+	if err := c.Validate(); !errors.Is(err, game.ErrRulesSpies) {
+		t.Fatalf("want: %v, have: %v", game.ErrRulesSpies, err)
+	}
+}
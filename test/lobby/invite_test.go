@@ -0,0 +1,114 @@
+package lobby
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/lobby"
+	"github.com/toms1441/resistance-server/internal/logger"
+	repository "github.com/toms1441/resistance-server/internal/repo"
+	"github.com/toms1441/resistance-server/internal/repo/plain"
+)
+
+func TestLobbyInvite(t *testing.T) {
+	ls, err := lobby.NewService(plain.NewLobbyRepository(), lobby.DefaultConfig)
+	if err != nil {
+		t.Fatalf("lobby.NewService: %v", err)
+	}
+
+	l := &lobby.Lobby{
+		Type:    lobby.TypeBasic,
+		Private: true,
+	}
+
+	if err := ls.CreateLobby(l); err != nil {
+		t.Fatalf("ls.CreateLobby: %v", err)
+	}
+	l.SetPassword("hunter2")
+
+	invite := l.GetInvite()
+	if len(invite) == 0 {
+		t.Fatal("the lobby doesn't have an invite")
+	}
+
+	if have, err := ls.GetLobbyByInvite(invite); err != nil || have != l {
+		t.Fatalf("ls.GetLobbyByInvite: %v", err)
+	}
+
+	if _, err := ls.GetLobbyByInvite(""); !errors.Is(err, repository.ErrLobby404) {
+		t.Fatalf("want: %v, have: %v", repository.ErrLobby404, err)
+	}
+
+	// the lobby id isn't enough to join a private lobby
+	if err := l.Authorize(cl.ID, "", ""); !errors.Is(err, lobby.ErrAccess) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrAccess, err)
+	}
+
+	if err := l.Authorize(cl.ID, "", "hunter3"); !errors.Is(err, lobby.ErrAccess) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrAccess, err)
+	}
+
+	if err := l.Authorize(cl.ID, "", "hunter2"); err != nil {
+		t.Fatalf("l.Authorize: %v", err)
+	}
+
+	if err := l.Authorize(cl.ID, invite, ""); err != nil {
+		t.Fatalf("l.Authorize: %v", err)
+	}
+
+	sc, c := conn.NewMockConnHelper(cl)
+	if err := l.Join(c); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	// rebinding adds the owner commands
+	if err := l.Rebind(c); err != nil {
+		t.Fatalf("l.Rebind: %v", err)
+	}
+
+	// clients in the lobby don't need the invite
+	if err := l.Authorize(cl.ID, "", ""); err != nil {
+		t.Fatalf("l.Authorize: %v", err)
+	}
+
+	rotated := make(chan string, 1)
+	sc.AddCommand("lobby", conn.MessageStruct{
+		"invite": func(log logger.Logger, bytes []byte) error {
+			var invite string
+			json.Unmarshal(bytes, &invite)
+
+			rotated <- invite
+			return nil
+		},
+	})
+
+	if err := c.ExecuteCommand("lobby", "rotate", nil); err != nil {
+		t.Fatalf("lobby.rotate: %v", err)
+	}
+
+	newinvite := <-rotated
+	if newinvite == invite || newinvite != l.GetInvite() {
+		t.Fatalf("old: %s, new: %s, lobby: %s", invite, newinvite, l.GetInvite())
+	}
+
+	// the old invite stops working
+	if err := l.Authorize("stranger", invite, ""); !errors.Is(err, lobby.ErrAccess) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrAccess, err)
+	}
+
+	if _, err := ls.GetLobbyByInvite(invite); !errors.Is(err, repository.ErrLobby404) {
+		t.Fatalf("want: %v, have: %v", repository.ErrLobby404, err)
+	}
+
+	if err := l.Authorize("stranger", newinvite, ""); err != nil {
+		t.Fatalf("l.Authorize: %v", err)
+	}
+
+	// public lobbies can be joined by anyone
+	l.Private = false
+	if err := l.Authorize("stranger", "", ""); err != nil {
+		t.Fatalf("l.Authorize: %v", err)
+	}
+}
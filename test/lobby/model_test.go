@@ -2,14 +2,17 @@ package lobby
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/toms1441/resistance-server/internal/client"
 	"github.com/toms1441/resistance-server/internal/conn"
 	"github.com/toms1441/resistance-server/internal/discord"
+	"github.com/toms1441/resistance-server/internal/game"
 	"github.com/toms1441/resistance-server/internal/lobby"
 	"github.com/toms1441/resistance-server/internal/logger"
+	repository "github.com/toms1441/resistance-server/internal/repo"
 )
 
 var cl = client.Client{
@@ -198,4 +201,297 @@ func TestLobbyValidate(t *testing.T) {
 	if err := lb.Validate(); err != nil {
 		t.Fatalf("lb.Validate: %v", err)
 	}
+
+	lb.Timers = "slow"
+	if err := lb.Validate(); err != lobby.ErrTimers {
+		t.Fatalf("want: %v, have: %v", lobby.ErrTimers, err)
+	}
+
+	lb.Timers = game.TimersBlitz
+	if err := lb.Validate(); err != nil {
+		t.Fatalf("lb.Validate: %v", err)
+	}
+	lb.Timers = ""
+
+	lb.BestOf = -1
+	if err := lb.Validate(); err != lobby.ErrBestOf {
+		t.Fatalf("want: %v, have: %v", lobby.ErrBestOf, err)
+	}
+	lb.BestOf = 0
+}
+
+func TestLobbyRebind(t *testing.T) {
+	l := &lobby.Lobby{}
+
+	_, oldc := conn.NewMockConnHelper(cl)
+	if err := l.Join(oldc); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	newsc, newc := conn.NewMockConnHelper(cl)
+
+	done := make(chan bool, 1)
+	newsc.AddCommand("lobby", conn.MessageStruct{
+		"get": func(log logger.Logger, bytes []byte) error {
+			done <- true
+			return nil
+		},
+	})
+
+	if err := l.Rebind(newc); err != nil {
+		t.Fatalf("l.Rebind: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Millisecond * 100):
+		t.Fatal("lobby.get was not sent to the new connection")
+	}
+
+	// the old connection closing doesn't remove the client, because it got replaced
+	oldc.Destroy()
+	time.Sleep(time.Millisecond * 10)
+
+	if l.GetClientIndex(cl.ID) == -1 {
+		t.Fatal("the client left the lobby when the old connection closed")
+	}
+
+	if err := l.Leave(oldc); err != repository.ErrClient404 {
+		t.Fatalf("want: %v, have: %v", repository.ErrClient404, err)
+	}
+
+	if l.GetClientIndex(cl.ID) == -1 {
+		t.Fatal("the old connection took the seat with it")
+	}
+
+	_, stranger := conn.NewMockConnHelper(client.Client{
+		User: discord.User{
+			ID: "stranger",
+		},
+	})
+
+	if err := l.Rebind(stranger); err != repository.ErrClient404 {
+		t.Fatalf("want: %v, have: %v", repository.ErrClient404, err)
+	}
+}
+
+func TestLobbyBots(t *testing.T) {
+	l := &lobby.Lobby{}
+
+	_, oldc := conn.NewMockConnHelper(cl)
+	if err := l.Join(oldc); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	// rebinding adds the owner commands
+	_, c := conn.NewMockConnHelper(cl)
+	if err := l.Rebind(c); err != nil {
+		t.Fatalf("l.Rebind: %v", err)
+	}
+
+	if err := c.ExecuteCommand("lobby", "addbot", []byte(`"clever"`)); !errors.Is(err, lobby.ErrStrategy) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrStrategy, err)
+	}
+
+	if err := c.ExecuteCommand("lobby", "addbot", []byte(`"aggressive"`)); err != nil {
+		t.Fatalf("lobby.addbot: %v", err)
+	}
+
+	if len(l.Clients) != 2 {
+		t.Fatalf("want: %d, have: %d", 2, len(l.Clients))
+	}
+
+	// the bot would never assassinate, hunt or use the lady of the lake
+	unlimited := []byte(`{"Timers": "unlimited"}`)
+	if err := c.ExecuteCommand("lobby", "update", unlimited); !errors.Is(err, lobby.ErrBotTimers) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrBotTimers, err)
+	}
+
+	id := ""
+	for _, v := range l.Clients {
+		if v.ID != cl.ID {
+			id = v.ID
+		}
+	}
+
+	body, _ := json.Marshal(cl.ID)
+	if err := c.ExecuteCommand("lobby", "removebot", body); err == nil {
+		t.Fatal("lobby.removebot removed a client that isn't a bot")
+	}
+
+	body, _ = json.Marshal(id)
+	if err := c.ExecuteCommand("lobby", "removebot", body); err != nil {
+		t.Fatalf("lobby.removebot: %v", err)
+	}
+
+	if len(l.Clients) != 1 {
+		t.Fatalf("want: %d, have: %d", 1, len(l.Clients))
+	}
+
+	if err := c.ExecuteCommand("lobby", "update", unlimited); err != nil {
+		t.Fatalf("lobby.update: %v", err)
+	}
+
+	if err := c.ExecuteCommand("lobby", "addbot", nil); !errors.Is(err, lobby.ErrBotTimers) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrBotTimers, err)
+	}
+}
+
+func TestLobbyUpdate(t *testing.T) {
+	l := &lobby.Lobby{}
+
+	_, oldc := conn.NewMockConnHelper(cl)
+	if err := l.Join(oldc); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	// rebinding adds the owner commands
+	_, c := conn.NewMockConnHelper(cl)
+	if err := l.Rebind(c); err != nil {
+		t.Fatalf("l.Rebind: %v", err)
+	}
+
+	update := l.SubscribeUpdate()
+	updated := make(chan bool, 1)
+	go func() {
+		for range update {
+			select {
+			case updated <- true:
+			default:
+			}
+		}
+	}()
+
+	body := []byte(`{"Type": 2, "Private": true, "Option": 14, "Timers": "blitz", "MaxPlayers": 7}`)
+	if err := c.ExecuteCommand("lobby", "update", body); err != nil {
+		t.Fatalf("lobby.update: %v", err)
+	}
+
+	want := game.OptionPercival | game.OptionMorgana | game.OptionAssassin
+	if l.Type != lobby.TypeAvalon || !l.Private || l.Option != want || l.Timers != game.TimersBlitz || l.MaxPlayers != 7 {
+		t.Fatalf("lobby wasn't updated: %+v", l)
+	}
+
+	select {
+	case <-updated:
+	case <-time.After(time.Second):
+		t.Fatal("SubscribeUpdate wasn't notified")
+	}
+
+	// fields that aren't in the body keep their value
+	if err := c.ExecuteCommand("lobby", "update", []byte(`{"Private": false}`)); err != nil {
+		t.Fatalf("lobby.update: %v", err)
+	}
+
+	if l.Private || l.Type != lobby.TypeAvalon || l.MaxPlayers != 7 {
+		t.Fatalf("lobby wasn't updated: %+v", l)
+	}
+
+	invalid := map[string]error{
+		`{"Type": 6}`:        lobby.ErrType,
+		`{"Timers": "slow"}`: lobby.ErrTimers,
+		`{"Option": 1}`:      lobby.ErrOption,
+		// the avalon roles of the lobby don't exist in TypeBasic
+		`{"Type": 0}`:              game.ErrAvalonOption,
+		`{"Type": 0, "Option": 2}`: game.ErrAvalonOption,
+		`{"MaxPlayers": 4}`:        lobby.ErrMaxPlayers,
+		`{"MaxPlayers": 11}`:       lobby.ErrMaxPlayers,
+	}
+
+	for body, want := range invalid {
+		if err := c.ExecuteCommand("lobby", "update", []byte(body)); !errors.Is(err, want) {
+			t.Fatalf("%s - want: %v, have: %v", body, want, err)
+		}
+	}
+
+	if l.Type != lobby.TypeAvalon || l.Timers != game.TimersBlitz || l.MaxPlayers != 7 {
+		t.Fatalf("an invalid update changed the lobby: %+v", l)
+	}
+}
+
+func TestLobbySpectate(t *testing.T) {
+	l := &lobby.Lobby{}
+
+	sc, c := conn.NewMockConnHelper(cl)
+	if err := l.Join(c); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	if err := l.Spectate(c, false); err != repository.ErrClientExists {
+		t.Fatalf("want: %v, have: %v", repository.ErrClientExists, err)
+	}
+
+	_, spectator := conn.NewMockConnHelper(client.Client{
+		User: discord.User{
+			ID: "spectator",
+		},
+	})
+
+	// lobbies that weren't created by the service don't have a caster delay
+	if err := l.Spectate(spectator, true); !errors.Is(err, lobby.ErrCaster) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrCaster, err)
+	}
+
+	if err := l.Spectate(spectator, false); err != nil {
+		t.Fatalf("l.Spectate: %v", err)
+	}
+
+	if len(l.Clients) != 1 || len(l.Spectators) != 1 {
+		t.Fatalf("clients: %d, spectators: %d", len(l.Clients), len(l.Spectators))
+	}
+
+	if err := l.Leave(spectator); err != nil {
+		t.Fatalf("l.Leave: %v", err)
+	}
+
+	if len(l.Clients) != 1 || len(l.Spectators) != 0 {
+		t.Fatalf("clients: %d, spectators: %d", len(l.Clients), len(l.Spectators))
+	}
+
+	// a spectator that joins becomes a player
+	if err := l.Spectate(spectator, false); err != nil {
+		t.Fatalf("l.Spectate: %v", err)
+	}
+
+	if err := l.Join(spectator); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	if len(l.Clients) != 2 || len(l.Spectators) != 0 {
+		t.Fatalf("clients: %d, spectators: %d", len(l.Clients), len(l.Spectators))
+	}
+
+	_, closing := conn.NewMockConnHelper(client.Client{
+		User: discord.User{
+			ID: "closing",
+		},
+	})
+
+	if err := l.Spectate(closing, false); err != nil {
+		t.Fatalf("l.Spectate: %v", err)
+	}
+
+	// spectators leave as soon as their connection closes
+	spectators := make(chan int, 1)
+	sc.AddCommand("lobby", conn.MessageStruct{
+		"get": func(log logger.Logger, bytes []byte) error {
+			body := lobby.Lobby{}
+			json.Unmarshal(bytes, &body)
+
+			spectators <- len(body.Spectators)
+			return nil
+		},
+	})
+
+	time.Sleep(time.Millisecond * 10)
+	closing.Destroy()
+
+	select {
+	case n := <-spectators:
+		if n != 0 {
+			t.Fatalf("want: %d, have: %d", 0, n)
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out")
+	}
 }
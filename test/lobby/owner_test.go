@@ -0,0 +1,211 @@
+package lobby
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/discord"
+	"github.com/toms1441/resistance-server/internal/lobby"
+	"github.com/toms1441/resistance-server/internal/logger"
+	repository "github.com/toms1441/resistance-server/internal/repo"
+)
+
+func TestLobbyOwner(t *testing.T) {
+	l := &lobby.Lobby{}
+
+	conns := map[string]conn.Conn{}
+	kicked := make(chan string, 1)
+	for _, id := range []string{"b", "a", "c"} {
+		sc, c := conn.NewMockConnHelper(client.Client{
+			User: discord.User{
+				ID: id,
+			},
+		})
+
+		sc.AddCommand("lobby", conn.MessageStruct{
+			"kick": func(log logger.Logger, bytes []byte) error {
+				var reason string
+				json.Unmarshal(bytes, &reason)
+
+				kicked <- reason
+				return nil
+			},
+		})
+
+		if err := l.Join(c); err != nil {
+			t.Fatalf("l.Join: %v", err)
+		}
+
+		conns[id] = c
+	}
+
+	// the first client that joins owns the lobby, not the first by id
+	if l.GetOwner() != "b" {
+		t.Fatalf("want: %s, have: %s", "b", l.GetOwner())
+	}
+
+	if err := conns["a"].ExecuteCommand("lobby", "transfer", []byte(`"a"`)); err == nil {
+		t.Fatal("a member was able to take the lobby")
+	}
+
+	if err := conns["b"].ExecuteCommand("lobby", "transfer", []byte(`"d"`)); !errors.Is(err, repository.ErrClient404) {
+		t.Fatalf("want: %v, have: %v", repository.ErrClient404, err)
+	}
+
+	if err := conns["b"].ExecuteCommand("lobby", "transfer", []byte(`"a"`)); err != nil {
+		t.Fatalf("lobby.transfer: %v", err)
+	}
+
+	if l.GetOwner() != "a" {
+		t.Fatalf("want: %s, have: %s", "a", l.GetOwner())
+	}
+
+	// the owner commands move along with the lobby
+	if err := conns["b"].ExecuteCommand("lobby", "kick", []byte(`{"user": {"id": "c"}}`)); err == nil {
+		t.Fatal("the old owner was able to kick")
+	}
+
+	if err := conns["a"].ExecuteCommand("lobby", "kick", []byte(`{"user": {"id": "a"}}`)); !errors.Is(err, lobby.ErrOwner) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrOwner, err)
+	}
+
+	if err := conns["a"].ExecuteCommand("lobby", "kick", []byte(`{"user": {"id": "d"}}`)); !errors.Is(err, repository.ErrClient404) {
+		t.Fatalf("want: %v, have: %v", repository.ErrClient404, err)
+	}
+
+	body, _ := json.Marshal(lobby.Kick{
+		Client: client.Client{User: discord.User{ID: "c"}},
+		Reason: "trolling",
+		Ban:    true,
+	})
+
+	if err := conns["a"].ExecuteCommand("lobby", "kick", body); err != nil {
+		t.Fatalf("lobby.kick: %v", err)
+	}
+
+	if reason := <-kicked; reason != "trolling" {
+		t.Fatalf("want: %s, have: %s", "trolling", reason)
+	}
+
+	if l.GetClientIndex("c") != -1 || len(l.Banned) != 1 {
+		t.Fatalf("clients: %v, banned: %v", l.Clients, l.Banned)
+	}
+
+	// banned clients can't come back
+	if err := l.Join(conns["c"]); !errors.Is(err, lobby.ErrBanned) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrBanned, err)
+	}
+
+	if err := l.Spectate(conns["c"], false); !errors.Is(err, lobby.ErrBanned) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrBanned, err)
+	}
+
+	if err := conns["a"].ExecuteCommand("lobby", "unban", []byte(`"c"`)); err != nil {
+		t.Fatalf("lobby.unban: %v", err)
+	}
+
+	if err := l.Join(conns["c"]); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	// the lobby goes to the first client by id once the owner leaves
+	if err := l.Leave(conns["a"]); err != nil {
+		t.Fatalf("l.Leave: %v", err)
+	}
+
+	if l.GetOwner() != "b" {
+		t.Fatalf("want: %s, have: %s", "b", l.GetOwner())
+	}
+
+	// the commands leave along with the client
+	if err := conns["a"].ExecuteCommand("lobby", "kick", []byte(`{"user": {"id": "c"}}`)); err == nil {
+		t.Fatal("the old owner was able to kick after leaving")
+	}
+
+	if err := conns["a"].ExecuteCommand("lobby", "get", nil); err == nil {
+		t.Fatal("the client was able to get the lobby after leaving")
+	}
+
+	if err := conns["b"].ExecuteCommand("lobby", "kick", []byte(`{"user": {"id": "c"}}`)); err != nil {
+		t.Fatalf("lobby.kick: %v", err)
+	}
+	<-kicked
+}
+
+func TestLobbyOwnerBots(t *testing.T) {
+	l := &lobby.Lobby{}
+
+	conns := map[string]conn.Conn{}
+	for _, id := range []string{"a", "x"} {
+		_, c := conn.NewMockConnHelper(client.Client{
+			User: discord.User{
+				ID: id,
+			},
+		})
+		conns[id] = c
+	}
+
+	if err := l.Join(conns["a"]); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	b := bot.NewBot(bot.Random{})
+	defer b.Destroy()
+
+	if err := l.Join(b); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	if err := l.Join(conns["x"]); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	// the bot comes first by id, but bots can't own the lobby
+	if err := l.Leave(conns["a"]); err != nil {
+		t.Fatalf("l.Leave: %v", err)
+	}
+
+	if l.GetOwner() != "x" {
+		t.Fatalf("want: %s, have: %s", "x", l.GetOwner())
+	}
+
+	// without anyone to take over, the next client that joins owns the lobby
+	if err := l.Leave(conns["x"]); err != nil {
+		t.Fatalf("l.Leave: %v", err)
+	}
+
+	if l.GetOwner() != "" {
+		t.Fatalf("want: %s, have: %s", "", l.GetOwner())
+	}
+
+	if err := l.Join(conns["x"]); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	if l.GetOwner() != "x" {
+		t.Fatalf("want: %s, have: %s", "x", l.GetOwner())
+	}
+
+	// a kicked bot gets destroyed, just like lobby.removebot
+	destroyed := make(chan bool)
+	go func(done chan bool) {
+		<-done
+		close(destroyed)
+	}(b.GetDone())
+
+	body, _ := json.Marshal(lobby.Kick{Client: b.GetClient()})
+	if err := conns["x"].ExecuteCommand("lobby", "kick", body); err != nil {
+		t.Fatalf("lobby.kick: %v", err)
+	}
+
+	select {
+	case <-destroyed:
+	case <-time.After(time.Second):
+		t.Fatal("the kicked bot wasn't destroyed")
+	}
+}
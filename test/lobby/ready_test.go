@@ -0,0 +1,202 @@
+package lobby
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/discord"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/lobby"
+	"github.com/toms1441/resistance-server/internal/logger"
+	"github.com/toms1441/resistance-server/internal/repo/plain"
+)
+
+func TestLobbyReadyCheck(t *testing.T) {
+	c := lobby.DefaultConfig
+	c.ReadyCheck = time.Millisecond * 100
+
+	ls, err := lobby.NewService(plain.NewLobbyRepository(), c)
+	if err != nil {
+		t.Fatalf("lobby.NewService: %v", err)
+	}
+
+	l := &lobby.Lobby{
+		Type:   lobby.TypeBasic,
+		Timers: game.TimersCasual,
+	}
+
+	if err := ls.CreateLobby(l); err != nil {
+		t.Fatalf("ls.CreateLobby: %v", err)
+	}
+
+	// the owner is a person, the rest are bots
+	sc, owner := conn.NewMockConnHelper(client.Client{
+		User: discord.User{
+			ID: "owner",
+		},
+	})
+
+	if err := l.Join(owner); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		b := bot.NewBot(bot.Random{})
+		defer b.Destroy()
+
+		if err := l.Join(b); err != nil {
+			t.Fatalf("l.Join: %v", err)
+		}
+	}
+
+	// rebinding adds the owner commands
+	if err := l.Rebind(owner); err != nil {
+		t.Fatalf("l.Rebind: %v", err)
+	}
+
+	unready := make(chan []string, 4)
+	sc.AddCommand("lobby", conn.MessageStruct{
+		"unready": func(log logger.Logger, bytes []byte) error {
+			ids := []string{}
+			json.Unmarshal(bytes, &ids)
+
+			unready <- ids
+			return nil
+		},
+	})
+
+	if err := owner.ExecuteCommand("lobby", "ready", nil); !errors.Is(err, lobby.ErrNoReadyCheck) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrNoReadyCheck, err)
+	}
+
+	// there's no ready check, so no one is ready
+	if err := owner.ExecuteCommand("lobby", "start", []byte("0")); !errors.Is(err, lobby.ErrNotReady) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrNotReady, err)
+	}
+
+	if ids := <-unready; len(ids) != 5 {
+		t.Fatalf("unready: %v", ids)
+	}
+
+	// the owner doesn't answer in time
+	if err := owner.ExecuteCommand("lobby", "readycheck", nil); err != nil {
+		t.Fatalf("lobby.readycheck: %v", err)
+	}
+
+	if ready := l.GetReady(); len(ready) != 5 || ready["owner"] {
+		t.Fatalf("l.GetReady: %v", ready)
+	}
+
+	select {
+	case ids := <-unready:
+		if !reflect.DeepEqual(ids, []string{"owner"}) {
+			t.Fatalf("unready: %v", ids)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the ready check didn't time out")
+	}
+
+	// answering late doesn't count
+	if err := owner.ExecuteCommand("lobby", "ready", nil); !errors.Is(err, lobby.ErrNoReadyCheck) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrNoReadyCheck, err)
+	}
+
+	if ready := l.GetReady(); len(ready) != 5 || ready["owner"] {
+		t.Fatalf("l.GetReady: %v", ready)
+	}
+
+	if err := owner.ExecuteCommand("lobby", "start", []byte("0")); !errors.Is(err, lobby.ErrNotReady) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrNotReady, err)
+	}
+
+	if ids := <-unready; !reflect.DeepEqual(ids, []string{"owner"}) {
+		t.Fatalf("unready: %v", ids)
+	}
+
+	// everyone is ready in time, the game can start even after the ready check is over
+	if err := owner.ExecuteCommand("lobby", "readycheck", nil); err != nil {
+		t.Fatalf("lobby.readycheck: %v", err)
+	}
+
+	if err := owner.ExecuteCommand("lobby", "ready", nil); err != nil {
+		t.Fatalf("lobby.ready: %v", err)
+	}
+
+	select {
+	case ids := <-unready:
+		t.Fatalf("unready: %v", ids)
+	case <-time.After(time.Millisecond * 200):
+	}
+
+	if err := owner.ExecuteCommand("lobby", "start", []byte("0")); err != nil {
+		t.Fatalf("lobby.start: %v", err)
+	}
+
+	if l.GetState() != lobby.StateInGame || l.GetReady() != nil {
+		t.Fatalf("state: %v, ready: %v", l.GetState(), l.GetReady())
+	}
+
+}
+
+func TestLobbyReadyMarshal(t *testing.T) {
+	c := lobby.DefaultConfig
+	c.ReadyCheck = time.Millisecond * 20
+
+	ls, err := lobby.NewService(plain.NewLobbyRepository(), c)
+	if err != nil {
+		t.Fatalf("lobby.NewService: %v", err)
+	}
+
+	l := &lobby.Lobby{
+		Type: lobby.TypeBasic,
+	}
+
+	if err := ls.CreateLobby(l); err != nil {
+		t.Fatalf("ls.CreateLobby: %v", err)
+	}
+
+	_, owner := conn.NewMockConnHelper(client.Client{
+		User: discord.User{
+			ID: "owner",
+		},
+	})
+
+	if err := l.Join(owner); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	// the routes marshal the lobby while the ready check changes it
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 20; i++ {
+			owner.ExecuteCommand("lobby", "readycheck", nil)
+			owner.ExecuteCommand("lobby", "ready", nil)
+		}
+
+		done <- true
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		body, err := json.Marshal(l)
+		if err != nil {
+			t.Fatalf("json.Marshal: %v", err)
+		}
+
+		s := lobby.Snapshot{}
+		if err := json.Unmarshal(body, &s); err != nil || s.ID != l.ID {
+			t.Fatalf("json.Unmarshal: %v, %+v", err, s)
+		}
+	}
+}
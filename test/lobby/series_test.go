@@ -0,0 +1,167 @@
+package lobby
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/discord"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/lobby"
+	"github.com/toms1441/resistance-server/internal/logger"
+	"github.com/toms1441/resistance-server/internal/repo/plain"
+)
+
+// waitState waits until the lobby is in the state.
+func waitState(t *testing.T, l *lobby.Lobby, state lobby.State) {
+	t.Helper()
+
+	timeout := time.After(time.Second * 2)
+	for l.GetState() != state {
+		select {
+		case <-timeout:
+			t.Fatalf("want: %v, have: %v", state, l.GetState())
+		case <-time.After(time.Millisecond * 5):
+		}
+	}
+}
+
+func TestLobbyRematch(t *testing.T) {
+	c := lobby.DefaultConfig
+	c.PostGame = time.Second * 5
+	// see TestLobbyReadyCheck
+	c.ReadyCheck = 0
+
+	ls, err := lobby.NewService(plain.NewLobbyRepository(), c)
+	if err != nil {
+		t.Fatalf("lobby.NewService: %v", err)
+	}
+
+	l := &lobby.Lobby{
+		Type:   lobby.TypeBasic,
+		Timers: game.TimersCasual,
+		BestOf: 2,
+	}
+
+	if err := ls.CreateLobby(l); err != nil {
+		t.Fatalf("ls.CreateLobby: %v", err)
+	}
+
+	var owner, last *bot.Bot
+	for i := 0; i < 5; i++ {
+		b := bot.NewBot(bot.Random{})
+		defer b.Destroy()
+
+		if err := l.Join(b); err != nil {
+			t.Fatalf("l.Join: %v", err)
+		}
+
+		if owner == nil {
+			owner = b
+		}
+		last = b
+	}
+
+	if err := l.Rebind(owner); err != nil {
+		t.Fatalf("l.Rebind: %v", err)
+	}
+
+	// the spectator gets the seats of every game
+	sc, spectator := conn.NewMockConnHelper(client.Client{
+		User: discord.User{
+			ID: "spectator",
+		},
+	})
+
+	seats := make(chan []string, 4)
+	sc.AddCommand("game", conn.MessageStruct{
+		"get": func(log logger.Logger, bytes []byte) error {
+			body := struct {
+				Seats []string `json:"seats"`
+			}{}
+			json.Unmarshal(bytes, &body)
+
+			seats <- body.Seats
+			return nil
+		},
+	})
+
+	if err := l.Spectate(spectator, false); err != nil {
+		t.Fatalf("l.Spectate: %v", err)
+	}
+
+	if err := owner.ExecuteCommand("lobby", "rematch", nil); !errors.Is(err, lobby.ErrTable) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrTable, err)
+	}
+
+	if err := owner.ExecuteCommand("lobby", "start", []byte("0")); err != nil {
+		t.Fatalf("lobby.start: %v", err)
+	}
+	waitState(t, l, lobby.StatePostGame)
+
+	if l.Series == nil || l.Series.Games != 1 || l.Series.IsOver() {
+		t.Fatalf("l.Series: %+v", l.Series)
+	}
+
+	// the rematch doesn't wait for the end of the post game
+	if err := owner.ExecuteCommand("lobby", "rematch", nil); err != nil {
+		t.Fatalf("lobby.rematch: %v", err)
+	}
+	waitState(t, l, lobby.StatePostGame)
+
+	if l.Series.Games != 2 || l.Series.Resistance+l.Series.Spies != 2 || !l.Series.IsOver() {
+		t.Fatalf("l.Series: %+v", l.Series)
+	}
+
+	wins := 0
+	for _, v := range l.Series.Wins {
+		wins += v
+	}
+
+	if wins == 0 {
+		t.Fatalf("l.Series.Wins: %v", l.Series.Wins)
+	}
+
+	first, second := <-seats, <-seats
+	if len(first) != 5 || !reflect.DeepEqual(first, second) {
+		t.Fatalf("seats - first: %v, second: %v", first, second)
+	}
+
+	// the table changed, so there's no rematch
+	if err := l.Leave(last); err != nil {
+		t.Fatalf("l.Leave: %v", err)
+	}
+
+	if err := owner.ExecuteCommand("lobby", "rematch", nil); !errors.Is(err, lobby.ErrTable) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrTable, err)
+	}
+}
+
+func TestSeriesIsOver(t *testing.T) {
+	tests := []struct {
+		series lobby.Series
+		want   bool
+	}{
+		{lobby.Series{BestOf: 0, Games: 5, Resistance: 5}, false},
+		{lobby.Series{BestOf: 3, Games: 1, Resistance: 1}, false},
+		{lobby.Series{BestOf: 3, Games: 2, Resistance: 1, Spies: 1}, false},
+		// the third game can't change the outcome
+		{lobby.Series{BestOf: 3, Games: 2, Resistance: 2}, true},
+		{lobby.Series{BestOf: 3, Games: 2, Spies: 2}, true},
+		{lobby.Series{BestOf: 2, Games: 1, Spies: 1}, false},
+		{lobby.Series{BestOf: 2, Games: 2, Resistance: 1, Spies: 1}, true},
+		{lobby.Series{BestOf: 5, Games: 3, Resistance: 3}, true},
+		{lobby.Series{BestOf: 5, Games: 4, Resistance: 2, Spies: 2}, false},
+	}
+
+	for k, v := range tests {
+		if have := v.series.IsOver(); have != v.want {
+			t.Fatalf("%d - want: %t, have: %t", k, v.want, have)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package lobby
 import (
 	"bytes"
 	"testing"
+	"time"
 
+	"github.com/toms1441/resistance-server/internal/bot"
 	"github.com/toms1441/resistance-server/internal/lobby"
 	"github.com/toms1441/resistance-server/internal/repo/plain"
 )
@@ -65,6 +67,20 @@ func TestServiceCreateLobby(t *testing.T) {
 
 }
 
+func TestServiceCreateLobbyRuleset(t *testing.T) {
+	if lobbyptr.Ruleset != lobby.RulesetOfficial {
+		t.Fatalf("lobbyptr.Ruleset - want: %s, have: %s", lobby.RulesetOfficial, lobbyptr.Ruleset)
+	}
+
+	err := lserv.CreateLobby(&lobby.Lobby{
+		Type:    lobby.TypeBasic,
+		Ruleset: "house",
+	})
+	if err != lobby.ErrRuleset {
+		t.Fatalf("want: %v, have: %v", lobby.ErrRuleset, err)
+	}
+}
+
 func TestServiceGetLobbyByID(t *testing.T) {
 
 	getlobby, err := lserv.GetLobbyByID(lobbyptr.ID)
@@ -130,3 +146,52 @@ func TestServiceRemoveLobby(t *testing.T) {
 		t.Fatalf("len(ls), want: %d - have: %d", 0, len(ls))
 	}
 }
+
+func TestServiceLeave(t *testing.T) {
+	ls, err := lobby.NewService(plain.NewLobbyRepository(), lobby.DefaultConfig)
+	if err != nil {
+		t.Fatalf("lobby.NewService: %v", err)
+	}
+
+	l := &lobby.Lobby{
+		Type: lobby.TypeBasic,
+	}
+
+	if err := ls.CreateLobby(l); err != nil {
+		t.Fatalf("ls.CreateLobby: %v", err)
+	}
+
+	bots := []*bot.Bot{}
+	for i := 0; i < 6; i++ {
+		b := bot.NewBot(bot.Random{})
+		defer b.Destroy()
+
+		if err := l.Join(b); err != nil {
+			t.Fatalf("l.Join: %v", err)
+		}
+
+		bots = append(bots, b)
+	}
+
+	// the service checks the lobby whenever a client leaves, it can't hold up the next client that leaves
+	done := make(chan bool)
+	go func() {
+		for _, b := range bots[:5] {
+			if err := l.Leave(b); err != nil {
+				t.Errorf("l.Leave: %v", err)
+			}
+		}
+
+		done <- true
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("l.Leave deadlocked")
+	}
+
+	if len(l.Clients) != 1 {
+		t.Fatalf("clients - want: %d, have: %d", 1, len(l.Clients))
+	}
+}
@@ -0,0 +1,167 @@
+package lobby
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/lobby"
+	"github.com/toms1441/resistance-server/internal/repo/plain"
+)
+
+func TestLobbyState(t *testing.T) {
+	c := lobby.DefaultConfig
+	c.PostGame = time.Millisecond * 200
+	// see TestLobbyReadyCheck
+	c.ReadyCheck = 0
+
+	ls, err := lobby.NewService(plain.NewLobbyRepository(), c)
+	if err != nil {
+		t.Fatalf("lobby.NewService: %v", err)
+	}
+
+	// the state can't be set by the clients
+	l := &lobby.Lobby{
+		Type:   lobby.TypeBasic,
+		Timers: game.TimersCasual,
+		State:  lobby.StateInGame,
+	}
+
+	if err := ls.CreateLobby(l); err != nil {
+		t.Fatalf("ls.CreateLobby: %v", err)
+	}
+
+	if l.GetState() != lobby.StateWaiting {
+		t.Fatalf("want: %v, have: %v", lobby.StateWaiting, l.GetState())
+	}
+
+	var owner *bot.Bot
+	for i := 0; i < 5; i++ {
+		b := bot.NewBot(bot.Random{})
+		defer b.Destroy()
+
+		if err := l.Join(b); err != nil {
+			t.Fatalf("l.Join: %v", err)
+		}
+
+		// the first client that joins owns the lobby
+		if owner == nil {
+			owner = b
+		}
+	}
+
+	// rebinding adds the owner commands
+	if err := l.Rebind(owner); err != nil {
+		t.Fatalf("l.Rebind: %v", err)
+	}
+
+	if err := owner.ExecuteCommand("lobby", "start", []byte("0")); err != nil {
+		t.Fatalf("lobby.start: %v", err)
+	}
+
+	if err := owner.ExecuteCommand("lobby", "start", []byte("0")); !errors.Is(err, lobby.ErrState) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrState, err)
+	}
+
+	if err := owner.ExecuteCommand("lobby", "addbot", nil); !errors.Is(err, lobby.ErrState) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrState, err)
+	}
+
+	// clients that join mid-game become spectators
+	_, late := conn.NewMockConnHelper(cl)
+	if err := l.Join(late); err != nil {
+		t.Fatalf("l.Join: %v", err)
+	}
+
+	if len(l.Clients) != 5 || len(l.Spectators) != 1 {
+		t.Fatalf("clients: %d, spectators: %d", len(l.Clients), len(l.Spectators))
+	}
+
+	seen := map[lobby.State]bool{}
+	timeout := time.After(time.Second * 2)
+	for l.GetState() != lobby.StateWaiting {
+		seen[l.GetState()] = true
+
+		select {
+		case <-timeout:
+			t.Fatalf("timed out, state: %v", l.GetState())
+		case <-time.After(time.Millisecond * 5):
+		}
+	}
+
+	if !seen[lobby.StateInGame] || !seen[lobby.StatePostGame] {
+		t.Fatalf("seen: %v", seen)
+	}
+
+	// the lobby can start a new game
+	if err := owner.ExecuteCommand("lobby", "addbot", nil); err != nil {
+		t.Fatalf("lobby.addbot: %v", err)
+	}
+}
+
+func TestLobbyStateLeave(t *testing.T) {
+	c := lobby.DefaultConfig
+	c.PostGame = time.Millisecond * 50
+	c.ReadyCheck = 0
+
+	ls, err := lobby.NewService(plain.NewLobbyRepository(), c)
+	if err != nil {
+		t.Fatalf("lobby.NewService: %v", err)
+	}
+
+	l := &lobby.Lobby{
+		Type:   lobby.TypeBasic,
+		Timers: game.TimersCasual,
+	}
+
+	if err := ls.CreateLobby(l); err != nil {
+		t.Fatalf("ls.CreateLobby: %v", err)
+	}
+
+	bots := []*bot.Bot{}
+	for i := 0; i < 5; i++ {
+		b := bot.NewBot(bot.Random{})
+		defer b.Destroy()
+
+		if err := l.Join(b); err != nil {
+			t.Fatalf("l.Join: %v", err)
+		}
+
+		bots = append(bots, b)
+	}
+
+	owner := bots[0]
+	if err := l.Rebind(owner); err != nil {
+		t.Fatalf("l.Rebind: %v", err)
+	}
+
+	if err := owner.ExecuteCommand("lobby", "start", []byte("0")); err != nil {
+		t.Fatalf("lobby.start: %v", err)
+	}
+
+	// the leaver keeps their seat until the game ends
+	leaver := bots[4]
+	if err := leaver.ExecuteCommand("lobby", "leave", nil); err != nil {
+		t.Fatalf("lobby.leave: %v", err)
+	}
+
+	timeout := time.After(time.Second * 2)
+	for l.GetState() != lobby.StateWaiting {
+		select {
+		case <-timeout:
+			t.Fatalf("timed out, state: %v", l.GetState())
+		case <-time.After(time.Millisecond * 5):
+		}
+	}
+
+	if i := l.GetClientIndex(leaver.GetClient().ID); i != -1 {
+		t.Fatalf("leaver is still in the lobby: %v", l.Clients)
+	}
+
+	if len(l.Clients) != 4 {
+		t.Fatalf("clients - want: %d, have: %d", 4, len(l.Clients))
+	}
+}
@@ -0,0 +1,112 @@
+package lobby
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/discord"
+	"github.com/toms1441/resistance-server/internal/lobby"
+	repository "github.com/toms1441/resistance-server/internal/repo"
+	"github.com/toms1441/resistance-server/internal/repo/plain"
+)
+
+func TestLobbyWaitlist(t *testing.T) {
+	c := lobby.DefaultConfig
+	c.MaxClient = 6
+
+	ls, err := lobby.NewService(plain.NewLobbyRepository(), c)
+	if err != nil {
+		t.Fatalf("lobby.NewService: %v", err)
+	}
+
+	// the per-lobby maximum can't go over the ceiling
+	if err := ls.CreateLobby(&lobby.Lobby{MaxPlayers: 7}); !errors.Is(err, lobby.ErrMaxPlayers) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrMaxPlayers, err)
+	}
+
+	l := &lobby.Lobby{MaxPlayers: 5}
+	if err := ls.CreateLobby(l); err != nil {
+		t.Fatalf("ls.CreateLobby: %v", err)
+	}
+
+	var owner, last *bot.Bot
+	for i := 0; i < 5; i++ {
+		b := bot.NewBot(bot.Random{})
+		defer b.Destroy()
+
+		if err := l.Join(b); err != nil {
+			t.Fatalf("l.Join: %v", err)
+		}
+
+		if owner == nil {
+			owner = b
+		}
+		last = b
+	}
+
+	// rebinding adds the owner commands
+	if err := l.Rebind(owner); err != nil {
+		t.Fatalf("l.Rebind: %v", err)
+	}
+
+	if err := owner.ExecuteCommand("lobby", "addbot", nil); !errors.Is(err, lobby.ErrLobbyFull) {
+		t.Fatalf("want: %v, have: %v", lobby.ErrLobbyFull, err)
+	}
+
+	waiting := []conn.Conn{}
+	for _, id := range []string{"first", "second", "third"} {
+		_, c := conn.NewMockConnHelper(client.Client{
+			User: discord.User{
+				ID: id,
+			},
+		})
+
+		if err := l.Join(c); !errors.Is(err, lobby.ErrLobbyFull) {
+			t.Fatalf("want: %v, have: %v", lobby.ErrLobbyFull, err)
+		}
+
+		if err := l.Wait(c); err != nil {
+			t.Fatalf("l.Wait: %v", err)
+		}
+
+		waiting = append(waiting, c)
+	}
+
+	if err := l.Wait(waiting[0]); !errors.Is(err, repository.ErrClientExists) {
+		t.Fatalf("want: %v, have: %v", repository.ErrClientExists, err)
+	}
+
+	if len(l.Waitlist) != 3 || l.Waitlist[0].ID != "first" {
+		t.Fatalf("l.Waitlist: %v", l.Waitlist)
+	}
+
+	// a bigger lobby takes the first client in the waitlist
+	if err := owner.ExecuteCommand("lobby", "update", []byte(`{"MaxPlayers": 6}`)); err != nil {
+		t.Fatalf("lobby.update: %v", err)
+	}
+
+	if l.GetClientIndex("first") == -1 || len(l.Clients) != 6 || len(l.Waitlist) != 2 {
+		t.Fatalf("clients: %v, waitlist: %v", l.Clients, l.Waitlist)
+	}
+
+	// the waitlist keeps its order
+	if err := l.Leave(waiting[2]); err != nil {
+		t.Fatalf("l.Leave: %v", err)
+	}
+
+	if len(l.Waitlist) != 1 || l.Waitlist[0].ID != "second" {
+		t.Fatalf("l.Waitlist: %v", l.Waitlist)
+	}
+
+	// a seat that frees up goes to the waitlist
+	if err := l.Leave(last); err != nil {
+		t.Fatalf("l.Leave: %v", err)
+	}
+
+	if l.GetClientIndex("second") == -1 || len(l.Clients) != 6 || len(l.Waitlist) != 0 {
+		t.Fatalf("clients: %v, waitlist: %v", l.Clients, l.Waitlist)
+	}
+}
@@ -0,0 +1,96 @@
+package replay
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/client"
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/discord"
+	"github.com/toms1441/resistance-server/internal/game"
+)
+
+var cl = client.Client{
+	User: discord.User{
+		ID:            "80351110224678912",
+		Username:      "Nelly",
+		Discriminator: "1337",
+	},
+}
+
+// recordConn is a conn.Conn that records every message that gets written to it.
+type recordConn struct {
+	conn.Conn
+
+	mtx  sync.Mutex
+	msgs []conn.MessageSend
+}
+
+func newRecordConn() *recordConn {
+	_, c := conn.NewMockConnHelper(cl)
+
+	return &recordConn{
+		Conn: c,
+	}
+}
+
+func (r *recordConn) WriteMessage(ms conn.MessageSend) error {
+	body, err := json.Marshal(ms)
+	if err != nil {
+		return err
+	}
+
+	// so the body is the same as what a client gets
+	ms = conn.MessageSend{}
+	json.Unmarshal(body, &ms)
+
+	r.mtx.Lock()
+	r.msgs = append(r.msgs, ms)
+	r.mtx.Unlock()
+
+	return nil
+}
+
+// names returns the names of the messages that have been written, in order.
+func (r *recordConn) names() []string {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	names := []string{}
+	for _, v := range r.msgs {
+		names = append(names, v.Name)
+	}
+
+	return names
+}
+
+// finishedGame plays a game with bots, and returns it once it's finished.
+func finishedGame(t *testing.T) *game.Game {
+	mapconn := map[string]conn.Conn{}
+	for i := 0; i < 5; i++ {
+		b := bot.NewBot(bot.Aggressive{})
+		t.Cleanup(b.Destroy)
+
+		mapconn[b.GetClient().ID] = b
+	}
+
+	g, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionNone)
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+	g.SetTimers(game.TimerPresets[game.TimersCasual])
+
+	done := make(chan game.Status)
+	go g.Run(done)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out")
+	}
+
+	return g
+}
@@ -0,0 +1,76 @@
+package replay
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/toms1441/resistance-server/internal/conn"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/replay"
+	"github.com/toms1441/resistance-server/internal/repo"
+	"github.com/toms1441/resistance-server/internal/repo/plain"
+)
+
+func TestReplayNew(t *testing.T) {
+	mapconn := map[string]conn.Conn{}
+	for i := 0; i < 5; i++ {
+		_, c := conn.NewMockConnHelper(cl)
+		mapconn[string(rune('a'+i))] = c
+	}
+
+	g, err := game.NewGame(mapconn, game.TypeBasic.Common(), game.OptionNone)
+	if err != nil {
+		t.Fatalf("game.NewGame: %v", err)
+	}
+
+	if _, err := replay.NewReplay(g); !errors.Is(err, replay.ErrGameRunning) {
+		t.Fatalf("want: %v, have: %v", replay.ErrGameRunning, err)
+	}
+
+	g = finishedGame(t)
+
+	r, err := replay.NewReplay(g)
+	if err != nil {
+		t.Fatalf("replay.NewReplay: %v", err)
+	}
+
+	if r.ID != g.ID || len(r.Clients) != 5 || len(r.Players) != 5 {
+		t.Fatalf("have: %+v", r)
+	}
+
+	// every role is revealed
+	for id, pt := range r.Players {
+		if g.Players[id].Type != pt {
+			t.Fatalf("r.Players[%s] - want: %d, have: %d", id, g.Players[id].Type, pt)
+		}
+	}
+
+	if last := r.Events[len(r.Events)-1]; last.Type != game.EventEnd || last.Status != r.Status {
+		t.Fatalf("last event: %+v", last)
+	}
+}
+
+func TestReplayService(t *testing.T) {
+	rserv, err := replay.NewService(plain.NewReplayRepository())
+	if err != nil {
+		t.Fatalf("replay.NewService: %v", err)
+	}
+
+	g := finishedGame(t)
+	if _, err := rserv.CreateReplay(g); err != nil {
+		t.Fatalf("rserv.CreateReplay: %v", err)
+	}
+
+	if _, err := rserv.CreateReplay(g); !errors.Is(err, repo.ErrReplayExists) {
+		t.Fatalf("want: %v, have: %v", repo.ErrReplayExists, err)
+	}
+
+	r, err := rserv.GetReplayByID(g.ID)
+	if err != nil || r.ID != g.ID {
+		t.Fatalf("rserv.GetReplayByID: %v", err)
+	}
+
+	if _, err := rserv.GetReplayByID("nothing"); !errors.Is(err, repo.ErrReplay404) {
+		t.Fatalf("want: %v, have: %v", repo.ErrReplay404, err)
+	}
+}
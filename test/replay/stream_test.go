@@ -0,0 +1,133 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/toms1441/resistance-server/internal/replay"
+	"github.com/toms1441/resistance-server/internal/repo/plain"
+)
+
+// wait waits until the conn has been written n messages.
+func wait(t *testing.T, rc *recordConn, n int) []string {
+	t.Helper()
+
+	timeout := time.After(time.Second * 2)
+	for {
+		names := rc.names()
+		if len(names) >= n {
+			return names
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("timed out, have: %v", names)
+		case <-time.After(time.Millisecond * 5):
+		}
+	}
+}
+
+func TestReplayStream(t *testing.T) {
+	rserv, err := replay.NewService(plain.NewReplayRepository())
+	if err != nil {
+		t.Fatalf("replay.NewService: %v", err)
+	}
+
+	r, err := rserv.CreateReplay(finishedGame(t))
+	if err != nil {
+		t.Fatalf("rserv.CreateReplay: %v", err)
+	}
+
+	rc := newRecordConn()
+	rserv.AddCommands(rc)
+
+	if err := rc.ExecuteCommand("replay", "forward", nil); !errors.Is(err, replay.ErrStream) {
+		t.Fatalf("want: %v, have: %v", replay.ErrStream, err)
+	}
+
+	body := []byte(fmt.Sprintf(`{"id": "%s", "speed": 1000}`, r.ID))
+	if err := rc.ExecuteCommand("replay", "start", body); !errors.Is(err, replay.ErrSpeed) {
+		t.Fatalf("want: %v, have: %v", replay.ErrSpeed, err)
+	}
+
+	body = []byte(fmt.Sprintf(`{"id": "%s", "speed": %d}`, r.ID, replay.MaxSpeed))
+	if err := rc.ExecuteCommand("replay", "start", body); err != nil {
+		t.Fatalf("rc.ExecuteCommand: %v", err)
+	}
+
+	// replay.get, every event then replay.end
+	total := len(r.Events) + 2
+	names := wait(t, rc, total)
+	if names[0] != "get" || names[total-1] != "end" {
+		t.Fatalf("names: %v", names)
+	}
+
+	for k, v := range names[1 : total-1] {
+		if v != "event" {
+			t.Fatalf("names[%d] - want: event, have: %s", k+1, v)
+		}
+	}
+
+	// the events are sent in order
+	rc.mtx.Lock()
+	for k, v := range rc.msgs[1 : total-1] {
+		bytes, _ := json.Marshal(v.Body)
+
+		index := struct {
+			Index int `json:"index"`
+		}{}
+		json.Unmarshal(bytes, &index)
+
+		if index.Index != k {
+			t.Fatalf("index - want: %d, have: %d", k, index.Index)
+		}
+	}
+	rc.mtx.Unlock()
+
+	if err := rc.ExecuteCommand("replay", "forward", nil); !errors.Is(err, replay.ErrStep) {
+		t.Fatalf("want: %v, have: %v", replay.ErrStep, err)
+	}
+
+	if err := rc.ExecuteCommand("replay", "back", nil); err != nil {
+		t.Fatalf("rc.ExecuteCommand: %v", err)
+	}
+
+	names = wait(t, rc, total+1)
+	if names[total] != "seek" {
+		t.Fatalf("want: seek, have: %s", names[total])
+	}
+
+	rc.mtx.Lock()
+	bytes, _ := json.Marshal(rc.msgs[total].Body)
+	rc.mtx.Unlock()
+
+	seek := []interface{}{}
+	json.Unmarshal(bytes, &seek)
+	if len(seek) != len(r.Events)-1 {
+		t.Fatalf("seek - want: %d, have: %d", len(r.Events)-1, len(seek))
+	}
+
+	if err := rc.ExecuteCommand("replay", "forward", nil); err != nil {
+		t.Fatalf("rc.ExecuteCommand: %v", err)
+	}
+
+	names = wait(t, rc, total+3)
+	if names[total+1] != "event" || names[total+2] != "end" {
+		t.Fatalf("names: %v", names[total:])
+	}
+
+	if err := rc.ExecuteCommand("replay", "speed", []byte("0.1")); !errors.Is(err, replay.ErrSpeed) {
+		t.Fatalf("want: %v, have: %v", replay.ErrSpeed, err)
+	}
+
+	if err := rc.ExecuteCommand("replay", "stop", nil); err != nil {
+		t.Fatalf("rc.ExecuteCommand: %v", err)
+	}
+
+	if err := rc.ExecuteCommand("replay", "play", nil); !errors.Is(err, replay.ErrStream) {
+		t.Fatalf("want: %v, have: %v", replay.ErrStream, err)
+	}
+}
@@ -0,0 +1,147 @@
+package simulate
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/toms1441/resistance-server/internal/bot"
+	"github.com/toms1441/resistance-server/internal/game"
+	"github.com/toms1441/resistance-server/internal/simulate"
+)
+
+func TestSimulateValidate(t *testing.T) {
+	c := simulate.Config{
+		Players:  []int{5},
+		Types:    []game.Type{game.TypeBasic},
+		Options:  []game.Option{game.OptionNone},
+		Strategy: bot.StrategyRandom,
+	}
+
+	if err := c.Validate(); err != simulate.ErrGames {
+		t.Fatalf("want: %v, have: %v", simulate.ErrGames, err)
+	}
+
+	c.Games = 1
+	c.Strategy = "clever"
+	if err := c.Validate(); err != simulate.ErrStrategy {
+		t.Fatalf("want: %v, have: %v", simulate.ErrStrategy, err)
+	}
+
+	c.Strategy = bot.StrategyRandom
+	c.Types = nil
+	if err := c.Validate(); err != simulate.ErrSetups {
+		t.Fatalf("want: %v, have: %v", simulate.ErrSetups, err)
+	}
+}
+
+func TestSimulateRun(t *testing.T) {
+	games := 10
+
+	report, err := simulate.Run(simulate.Config{
+		Games:    games,
+		Players:  []int{5},
+		Types:    []game.Type{game.TypeBasic},
+		Options:  []game.Option{game.OptionNone, game.OptionPercival},
+		Strategy: bot.StrategyAggressive,
+	})
+	if err != nil {
+		t.Fatalf("simulate.Run: %v", err)
+	}
+
+	if len(report.Results) != 2 {
+		t.Fatalf("want: %d, have: %d", 2, len(report.Results))
+	}
+
+	r := report.Results[0]
+	if r.Games != games || r.Resistance+r.Spies != games {
+		t.Fatalf("games - want: %d, have: %d, %d resistance and %d spies", games, r.Games, r.Resistance, r.Spies)
+	}
+
+	// 5 players have 3 resistance and 2 spies
+	resistance, spy := r.Roles[game.PlayerTypeResistance.String()], r.Roles[game.PlayerTypeSpy.String()]
+	if resistance == nil || spy == nil || resistance.Games != games*3 || spy.Games != games*2 {
+		t.Fatalf("roles - have: %+v", r.Roles)
+	}
+
+	if resistance.Wins != r.Resistance*3 || spy.Wins != r.Spies*2 {
+		t.Fatalf("wins - have: %+v", r.Roles)
+	}
+
+	// percival needs avalon
+	if report.Results[1].Games != 0 || len(report.Results[1].Error) == 0 {
+		t.Fatalf("the setup that can't be played was played: %+v", report.Results[1])
+	}
+}
+
+func TestSimulateReport(t *testing.T) {
+	report, err := simulate.Run(simulate.Config{
+		Games:    2,
+		Players:  []int{5},
+		Types:    []game.Type{game.TypeBasic},
+		Options:  []game.Option{game.OptionNone},
+		Strategy: bot.StrategyCautious,
+	})
+	if err != nil {
+		t.Fatalf("simulate.Run: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := report.Write(buf, simulate.FormatJSON); err != nil {
+		t.Fatalf("report.Write: %v", err)
+	}
+
+	have := simulate.Report{}
+	if err := json.Unmarshal(buf.Bytes(), &have); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+
+	if len(have.Results) != 1 || have.Results[0].Games != 2 {
+		t.Fatalf("json - have: %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := report.Write(buf, simulate.FormatCSV); err != nil {
+		t.Fatalf("report.Write: %v", err)
+	}
+
+	rows, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatalf("csv.ReadAll: %v", err)
+	}
+
+	// the header, then resistance and spy
+	if len(rows) != 3 || rows[1][1] != game.TypeBasic.String() || rows[1][7] != game.PlayerTypeResistance.String() {
+		t.Fatalf("csv - have: %v", rows)
+	}
+
+	if err := report.Write(buf, "xml"); err == nil {
+		t.Fatal("report.Write: wrote an unknown format")
+	}
+}
+
+func TestSimulateParse(t *testing.T) {
+	players, err := simulate.ParsePlayers("5, 7,10")
+	if err != nil || len(players) != 3 || players[2] != 10 {
+		t.Fatalf("simulate.ParsePlayers - have: %v, %v", players, err)
+	}
+
+	types, err := simulate.ParseTypes("basic,Avalon")
+	if err != nil || len(types) != 2 || types[1] != game.TypeAvalon {
+		t.Fatalf("simulate.ParseTypes - have: %v, %v", types, err)
+	}
+
+	if _, err := simulate.ParseTypes("chess"); err == nil {
+		t.Fatal("simulate.ParseTypes parsed an unknown type")
+	}
+
+	options, err := simulate.ParseOptions("none,percival+morgana")
+	if err != nil || len(options) != 2 || options[0] != game.OptionNone || options[1] != game.OptionPercival|game.OptionMorgana {
+		t.Fatalf("simulate.ParseOptions - have: %v, %v", options, err)
+	}
+
+	if _, err := simulate.ParseOptions("excalibur"); err == nil {
+		t.Fatal("simulate.ParseOptions parsed an unknown option")
+	}
+}